@@ -26,16 +26,24 @@ const (
 	Sawtooth
 	Abs
 	Softmax
+	Gaussian
+	Square
 )
 
 // AllSingleActivations is a list of all possible activations that can be applied to a single value,
-// i.e. they can be used in Activate()
+// i.e. they can be used in Activate().
+// Gaussian and Square can also be applied to a single value, but they were added later and are left out of this list,
+// so that existing NEAT and CGP setups that are given this list keep choosing from the same activations.
 var AllSingleActivations = []Activation{Relu, Linear, Sigmoid, Tanh, Sin, Cos, Binary, Reln, Relum, Sawtooth, Abs}
 
+// AllCPPNActivations is a list of the activations usually used in the hidden neurons of a CPPN, such as a [NeatGenotype] decoded by a [Substrate].
+// They are a mix of symmetric, periodic and monotonic functions, so the CPPN can create regular patterns.
+var AllCPPNActivations = []Activation{Linear, Sigmoid, Tanh, Sin, Gaussian, Abs, Square}
+
 // AllLayerActivations is a list of all possible activations that can be applied to a vector,
 // i.e. they can be used in ActivateVecor.
-// This is a superset of [AllSingleActivations].
-var AllVectorActivations = append([]Activation{Softmax}, AllSingleActivations...)
+// This is a superset of [AllSingleActivations] and [AllCPPNActivations].
+var AllVectorActivations = slices.Concat([]Activation{Softmax}, AllSingleActivations, []Activation{Gaussian, Square})
 
 // String returns the string representation of the activation.
 func (a Activation) String() string {
@@ -64,6 +72,10 @@ func (a Activation) String() string {
 		return "abs"
 	case Softmax:
 		return "softmax"
+	case Gaussian:
+		return "gaussian"
+	case Square:
+		return "square"
 	}
 	panic("unknown activation")
 }
@@ -71,7 +83,7 @@ func (a Activation) String() string {
 // CanSingleApply returns if the activation can be applied to a single value,
 // i.e. with [Activate]
 func (a Activation) CanSingleApply() bool {
-	return slices.Contains(AllSingleActivations, a)
+	return a != Softmax && slices.Contains(AllVectorActivations, a)
 }
 
 // Activate applies the activation function to the given value.
@@ -119,6 +131,10 @@ func (a Activation) ActivateValue(x float64) float64 {
 		return x - xr
 	case Abs:
 		return math.Abs(x)
+	case Gaussian:
+		return math.Exp(-x * x)
+	case Square:
+		return x * x
 	case Softmax:
 		panic(fmt.Sprintf("the activation '%s' is not supported for activating a single node", a))
 	}
@@ -183,6 +199,10 @@ func (a *Activation) UnmarshalJSON(bs []byte) error {
 		*a = Abs
 	case Softmax.String():
 		*a = Softmax
+	case Gaussian.String():
+		*a = Gaussian
+	case Square.String():
+		*a = Square
 	default:
 		return fmt.Errorf("invalid activation: '%s'", s)
 	}
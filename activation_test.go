@@ -28,3 +28,9 @@ func TestActivationLoading(t *testing.T) {
 		}
 	}
 }
+
+func TestActivationCanSingleApply(t *testing.T) {
+	for _, a := range AllVectorActivations {
+		assertEq(t, a.CanSingleApply(), a != Softmax, a.String())
+	}
+}
@@ -5,6 +5,15 @@ package goevo
 type Agent[T any] struct {
 	Genotype T
 	Fitness  float64
+	// Objectives is the fitness vector of the agent, used by multi-objective populations such as [NSGA2Population].
+	// Each objective is maximised. Single-objective populations ignore it.
+	Objectives []float64
+	// Behaviour is a characterisation of what the agent did when it was evaluated, used by behaviour-based selections such as novelty search.
+	// Each agent's behaviour should have the same length.
+	Behaviour []float64
+	// Age is the number of generations that the agent's genetic material has been in the population, used by age-layered populations such as [ALPSPopulation].
+	// New agents start with an age of 0. Populations that do not track age leave it at 0.
+	Age int
 }
 
 // NewAgent creates a new agent with the given genotype.
@@ -0,0 +1,180 @@
+package goevo
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// speciesPopulation is implemented by populations that group their agents into species, such as [SpeciatedPopulation].
+type speciesPopulation[T any] interface {
+	AllSpecies() map[int][]*Agent[T]
+}
+
+// Checkpoint is a snapshot of a population that can be saved to disk, and later used to continue the run.
+// It stores the agents and their fitnesses, but not the selection or reproduction strategies,
+// as these are supplied again when rebuilding the population.
+type Checkpoint[T any] struct {
+	// Generation is the generation number that the population was at when the checkpoint was taken.
+	Generation int
+	// Counter is the value of the counter used by the run, which can be restored with [Checkpoint.NewCounter].
+	Counter int
+	// Agents are all the agents in the population.
+	Agents []*Agent[T]
+	// SpeciesIDs are the species ID of each agent in Agents.
+	// For populations without species, all IDs are 0.
+	SpeciesIDs []int
+}
+
+// NewCheckpoint creates a new [Checkpoint] of the population.
+// The counter may be nil if the run does not use one.
+// The checkpoint shares its agents with the population, so it should be written before the agents are modified.
+func NewCheckpoint[T any](pop Population[T], counter *Counter, generation int) *Checkpoint[T] {
+	c := &Checkpoint[T]{Generation: generation}
+	if counter != nil {
+		c.Counter = counter.Value()
+	}
+	if sp, ok := pop.(speciesPopulation[T]); ok {
+		species := sp.AllSpecies()
+		for _, id := range sortedKeys(species) {
+			for _, a := range species[id] {
+				c.Agents = append(c.Agents, a)
+				c.SpeciesIDs = append(c.SpeciesIDs, id)
+			}
+		}
+	} else {
+		c.Agents = slices.Clone(pop.All())
+		c.SpeciesIDs = make([]int, len(c.Agents))
+	}
+	return c
+}
+
+// savedAgent is the on-disk representation of an agent.
+type savedAgent struct {
+	Genotype []byte
+	Fitness  float64
+	Species  int
+	Age      int
+}
+
+// savedCheckpoint is the on-disk representation of a checkpoint.
+type savedCheckpoint struct {
+	Generation int
+	Counter    int
+	Agents     []savedAgent
+}
+
+// Write writes the checkpoint to w, using the codec to encode the genotypes.
+func (c *Checkpoint[T]) Write(w io.Writer, codec Codec[T]) error {
+	if len(c.Agents) != len(c.SpeciesIDs) {
+		return fmt.Errorf("checkpoint has %v agents but %v species ids", len(c.Agents), len(c.SpeciesIDs))
+	}
+	sc := savedCheckpoint{
+		Generation: c.Generation,
+		Counter:    c.Counter,
+		Agents:     make([]savedAgent, len(c.Agents)),
+	}
+	for i, a := range c.Agents {
+		bs, err := codec.Encode(a.Genotype)
+		if err != nil {
+			return fmt.Errorf("failed to encode genotype of agent %v: %v", i, err)
+		}
+		sc.Agents[i] = savedAgent{bs, a.Fitness, c.SpeciesIDs[i], a.Age}
+	}
+	return gob.NewEncoder(w).Encode(&sc)
+}
+
+// WriteFile writes the checkpoint to the file at path, using the codec to encode the genotypes.
+// The file is written to a temporary file first and then renamed, so a crash while writing will never leave a corrupt checkpoint at path.
+func (c *Checkpoint[T]) WriteFile(path string, codec Codec[T]) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := c.Write(f, codec); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// ReadCheckpoint reads a [Checkpoint] that was written with [Checkpoint.Write], using the codec to decode the genotypes.
+func ReadCheckpoint[T any](r io.Reader, codec Codec[T]) (*Checkpoint[T], error) {
+	sc := savedCheckpoint{}
+	if err := gob.NewDecoder(r).Decode(&sc); err != nil {
+		return nil, err
+	}
+	c := &Checkpoint[T]{
+		Generation: sc.Generation,
+		Counter:    sc.Counter,
+		Agents:     make([]*Agent[T], len(sc.Agents)),
+		SpeciesIDs: make([]int, len(sc.Agents)),
+	}
+	for i, sa := range sc.Agents {
+		g, err := codec.Decode(sa.Genotype)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode genotype of agent %v: %v", i, err)
+		}
+		c.Agents[i] = &Agent[T]{Genotype: g, Fitness: sa.Fitness, Age: sa.Age}
+		c.SpeciesIDs[i] = sa.Species
+	}
+	return c, nil
+}
+
+// ReadCheckpointFile reads a [Checkpoint] from the file at path, using the codec to decode the genotypes.
+func ReadCheckpointFile[T any](path string, codec Codec[T]) (*Checkpoint[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCheckpoint(f, codec)
+}
+
+// NewCounter returns a new [Counter] that continues from where the checkpointed counter was.
+func (c *Checkpoint[T]) NewCounter() *Counter {
+	return NewCounterFrom(c.Counter)
+}
+
+// Species returns the agents of the checkpoint grouped by their species ID.
+func (c *Checkpoint[T]) Species() map[int][]*Agent[T] {
+	species := make(map[int][]*Agent[T])
+	for i, a := range c.Agents {
+		species[c.SpeciesIDs[i]] = append(species[c.SpeciesIDs[i]], a)
+	}
+	return species
+}
+
+// SimplePopulation rebuilds a [SimplePopulation] from the checkpoint, using the given selection and reproduction strategies.
+func (c *Checkpoint[T]) SimplePopulation(selection Selection[T], reproduction Reproduction[T]) *SimplePopulation[T] {
+	return NewSimplePopulationFrom(c.Agents, selection, reproduction)
+}
+
+// SpeciatedPopulation rebuilds a [SpeciatedPopulation] from the checkpoint, using the given parameters and selection and reproduction strategies.
+// The counter should usually be created with [Checkpoint.NewCounter], so that new species do not reuse old IDs.
+func (c *Checkpoint[T]) SpeciatedPopulation(
+	rng *rand.Rand,
+	counter *Counter,
+	removeWorstSpeciesChance float64,
+	stdNumAgentsSwap float64,
+	selection Selection[T],
+	reproduction Reproduction[T],
+) *SpeciatedPopulation[T] {
+	return NewSpeciatedPopulationFrom(rng, counter, c.Species(), removeWorstSpeciesChance, stdNumAgentsSwap, selection, reproduction)
+}
+
+// HillClimberPopulation rebuilds a [HillClimberPopulation] from the checkpoint, using the given selection and reproduction strategies.
+func (c *Checkpoint[T]) HillClimberPopulation(selection Selection[T], reproduction Reproduction[T]) *HillClimberPopulation[T] {
+	if len(c.Agents) != 2 {
+		panic("hill climber checkpoint must have exactly 2 agents")
+	}
+	return NewHillClimberPopulationFrom(c.Agents[0], c.Agents[1], selection, reproduction)
+}
@@ -0,0 +1,85 @@
+package goevo
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+// Check that a speciated neat population survives being saved and loaded
+func TestCheckpointSpeciated(t *testing.T) {
+	rng := NewRand(0)
+	counter := NewCounter()
+	selec := NewTournamentSelection[*NeatGenotype](rng, 3)
+	mut := NewNeatMutationStd(rng, counter, AllSingleActivations, 1, 0, 0.5, 2, 0, 0.5, 0.2, 0.4, 3)
+	reprod := NewTwoPhaseReproduction(NewNeatCrossoverSimple(rng), mut)
+	pop := NewSpeciatedPopulation(rng, counter, func() *NeatGenotype {
+		gt := NewNeatGenotype(counter, 3, 1, Sigmoid)
+		gt.AddRandomSynapse(rng, counter, 0.3, false)
+		return gt
+	}, 3, 5, 0.1, 1, selec, reprod)
+	pop = NextGeneration(pop)
+	for i, a := range pop.All() {
+		a.Fitness = float64(i)
+	}
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	if err := NewCheckpoint(pop, counter, 2).WriteFile(path, NewJSONCodec[*NeatGenotype]()); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := ReadCheckpointFile(path, NewJSONCodec[*NeatGenotype]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, cp.Generation, 2, "generation")
+	assertEq(t, cp.NewCounter().Next(), counter.Next(), "counter")
+
+	loaded := cp.SpeciatedPopulation(rng, cp.NewCounter(), 0.1, 1, selec, reprod)
+	originalSpecies, loadedSpecies := pop.AllSpecies(), loaded.AllSpecies()
+	assertEq(t, len(loadedSpecies), len(originalSpecies), "num species")
+	for id, agents := range originalSpecies {
+		assertEq(t, len(loadedSpecies[id]), len(agents), "species size")
+		for i, a := range agents {
+			la := loadedSpecies[id][i]
+			assertEq(t, la.Fitness, a.Fitness, "fitness")
+			if err := la.Genotype.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			input := []float64{1, 0.5, -1}
+			assertEq(t, la.Genotype.Build().Forward(input)[0], a.Genotype.Build().Forward(input)[0], "output")
+		}
+	}
+	// Make sure the loaded population can continue
+	NextGeneration(loaded)
+}
+
+// Check that a hill climber keeps its agents in order
+func TestCheckpointHillClimber(t *testing.T) {
+	selec := NewEliteSelection[int]()
+	reprod := NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{})
+	pop := NewHillClimberPopulation(1, 2, selec, reprod)
+	pop.a.Fitness, pop.b.Fitness = 10, 20
+
+	buf := new(bytes.Buffer)
+	if err := NewCheckpoint(pop, nil, 5).Write(buf, NewJSONCodec[int]()); err != nil {
+		t.Fatal(err)
+	}
+	cp, err := ReadCheckpoint(buf, NewJSONCodec[int]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := cp.HillClimberPopulation(selec, reprod).Both()
+	assertEq(t, a.Genotype, 1, "agent a genotype")
+	assertEq(t, a.Fitness, 10.0, "agent a fitness")
+	assertEq(t, b.Genotype, 2, "agent b genotype")
+	assertEq(t, b.Fitness, 20.0, "agent b fitness")
+}
+
+type intCrossoverAsexual struct{}
+
+func (*intCrossoverAsexual) Crossover(gs []int) int { return gs[0] }
+func (*intCrossoverAsexual) NumParents() int        { return 1 }
+
+type intMutationNone struct{}
+
+func (*intMutationNone) Mutate(int) {}
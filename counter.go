@@ -14,6 +14,18 @@ func NewCounter() *Counter {
 	return &Counter{0}
 }
 
+// NewCounterFrom creates a new counter, starting at n.
+// The first call to [Counter.Next] will return n+1.
+// This is useful to continue a counter that was saved with [Counter.Value].
+func NewCounterFrom(n int) *Counter {
+	return &Counter{int64(n)}
+}
+
+// Value returns the last value returned by [Counter.Next] without changing the counter.
+func (c *Counter) Value() int {
+	return int(atomic.LoadInt64(&c.n))
+}
+
 // Next returns the next value of the counter
 func (c *Counter) Next() int {
 	return int(atomic.AddInt64(&c.n, 1))
@@ -7,42 +7,60 @@ type Generator[T any] interface {
 }
 
 type generatorNormal[T floatType] struct {
+	rng  *rand.Rand
 	mean float64
 	std  float64
 }
 
 // NewGeneratorNormal creates a new [Generator] that generates floating point numbers
 // within a normal distribution.
-func NewGeneratorNormal[T floatType](mean, std T) Generator[T] {
+func NewGeneratorNormal[T floatType](rng *rand.Rand, mean, std T) Generator[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
 	if std < 0 {
 		panic("cannot have std < 0")
 	}
 	return &generatorNormal[T]{
+		rng:  rng,
 		mean: float64(mean),
 		std:  float64(std),
 	}
 }
 
 func (s *generatorNormal[T]) Next() T {
-	v := rand.NormFloat64()*s.std + s.mean
+	v := s.rng.NormFloat64()*s.std + s.mean
 	return T(v)
 }
 
+// WithScaledStrength implements [Scalable] by scaling the standard deviation.
+// The copy shares the random source of the original.
+func (s *generatorNormal[T]) WithScaledStrength(factor float64) any {
+	c := *s
+	c.std *= factor
+	return &c
+}
+
 type generatorChoice[T any] struct {
+	rng     *rand.Rand
 	choices []T
 }
 
 // NewGeneratorChoices creates a new [Generator] that chooses values from
 // the given choices slice.
-func NewGeneratorChoices[T any](choices []T) Generator[T] {
+func NewGeneratorChoices[T any](rng *rand.Rand, choices []T) Generator[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
 	if len(choices) == 0 {
 		panic("cannot have no choices")
 	}
 	return &generatorChoice[T]{
+		rng:     rng,
 		choices: choices,
 	}
 }
 
 func (c *generatorChoice[T]) Next() T {
-	return c.choices[rand.IntN(len(c.choices))]
+	return c.choices[c.rng.IntN(len(c.choices))]
 }
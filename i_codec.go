@@ -0,0 +1,10 @@
+package goevo
+
+// Codec is an interface for a strategy that converts genotypes with type T to and from bytes.
+// It is used to save genotypes to disk, for example in a [Checkpoint].
+type Codec[T any] interface {
+	// Encode converts the genotype to bytes.
+	Encode(T) ([]byte, error)
+	// Decode converts bytes created by Encode back to a genotype.
+	Decode([]byte) (T, error)
+}
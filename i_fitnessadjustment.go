@@ -0,0 +1,9 @@
+package goevo
+
+// FitnessAdjustment is a strategy for changing the fitness that a [Selection] sees, such as fitness sharing or scaling.
+// It is used with [NewAdjustedSelection], and never modifies the agents themselves.
+type FitnessAdjustment[T any] interface {
+	// Adjust returns the adjusted score of each agent, given their current scores.
+	// The scores start as the raw fitnesses, but may have already been changed by an earlier adjustment.
+	Adjust(agents []*Agent[T], scores []float64) []float64
+}
@@ -0,0 +1,8 @@
+package goevo
+
+// MigrationTopology is a strategy that decides which islands of an [IslandPopulation] send migrants to which others.
+type MigrationTopology interface {
+	// Destinations returns the indices of the islands that the island at index from sends its migrants to,
+	// when there are numIslands islands in total.
+	Destinations(from, numIslands int) []int
+}
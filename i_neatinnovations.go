@@ -0,0 +1,13 @@
+package goevo
+
+// NeatInnovations is a source of IDs for the structural mutations of a [NeatGenotype].
+// A [*Counter] gives every new neuron and synapse a fresh ID, whereas a [NeatInnovationTracker] gives the same ID to the same structural change in different genotypes.
+// Implementations must be safe to use from multiple goroutines.
+type NeatInnovations interface {
+	// Next returns a fresh ID that has never been used before.
+	Next() int
+	// SynapseID returns the ID for a new synapse with the given endpoints.
+	SynapseID(ep NeatSynapseEP) NeatSynapseID
+	// SplitNeuronID returns the ID for a new neuron that is added by splitting the synapse with the given ID.
+	SplitNeuronID(split NeatSynapseID) NeatNeuronID
+}
@@ -14,6 +14,16 @@ type Population[T any] interface {
 	All() []*Agent[T]
 }
 
+// IncrementalPopulation is a [Population] where only some of the agents need to be evaluated each generation,
+// such as [SteadyStatePopulation]. The [Runner] only evaluates the agents returned by Unevaluated.
+type IncrementalPopulation[T any] interface {
+	Population[T]
+
+	// Unevaluated returns the agents that have not yet been evaluated.
+	// These must also be returned by All.
+	Unevaluated() []*Agent[T]
+}
+
 func NextGeneration[T any, U Population[T]](pop U) U {
 	return pop.NextGeneration().(U)
 }
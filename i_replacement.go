@@ -0,0 +1,8 @@
+package goevo
+
+// Replacement is a strategy for choosing which member of a [SteadyStatePopulation] or neighbourhood of a [CellularPopulation] a new child replaces.
+type Replacement[T any] interface {
+	// Replace returns the index of the member that the child should replace, or -1 if the child should be discarded.
+	// The members are ordered from oldest to newest (or with the child's own cell first in a [CellularPopulation]), and both the members and the child have been evaluated.
+	Replace(members []*Agent[T], child *Agent[T]) int
+}
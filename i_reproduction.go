@@ -24,3 +24,32 @@ type Reproduction[T any] interface {
 	// NumParents returns the number of parents required for this reproduction strategy
 	NumParents() int
 }
+
+// AgentCrossover is a [Crossover] that can also use the parent agents, for example to favour the genes of the fitter parent.
+type AgentCrossover[T any] interface {
+	Crossover[T]
+	// CrossoverAgents performs a crossover like Crossover, but is given the parent agents (including their fitness) instead of just their genotypes.
+	// The agents must not be modified.
+	CrossoverAgents([]*Agent[T]) T
+}
+
+// AgentReproduction is a [Reproduction] that can also use the parent agents, usually because it uses an [AgentCrossover].
+type AgentReproduction[T any] interface {
+	Reproduction[T]
+	// ReproduceAgents creates a child like Reproduce, but is given the parent agents (including their fitness) instead of just their genotypes.
+	// The agents must not be modified.
+	ReproduceAgents([]*Agent[T]) T
+}
+
+// ReproduceAgents creates a child from the parent agents using the reproduction.
+// If the reproduction is an [AgentReproduction], it is given the agents, otherwise it is given their genotypes.
+func ReproduceAgents[T any](reproduction Reproduction[T], parents []*Agent[T]) T {
+	if ar, ok := reproduction.(AgentReproduction[T]); ok {
+		return ar.ReproduceAgents(parents)
+	}
+	gts := make([]T, len(parents))
+	for i, a := range parents {
+		gts[i] = a.Genotype
+	}
+	return reproduction.Reproduce(gts)
+}
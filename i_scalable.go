@@ -0,0 +1,10 @@
+package goevo
+
+// Scalable is an interface for mutations (and the things they are built from) whose strength can be changed while running.
+// For example, [HillClimberPopulation.WithSuccessRule] uses it to apply the 1/5th success rule.
+type Scalable interface {
+	// WithScaledStrength returns a copy of the mutation with its strength, such as the standard deviation of the noise it adds, multiplied by factor.
+	// The original is not modified, so it can still be shared with other populations.
+	// Like [Cloneable.Clone], the copy has the same type as the original.
+	WithScaledStrength(factor float64) any
+}
@@ -0,0 +1,7 @@
+package goevo
+
+// StatsWriter is an interface for a destination that [GenerationStats] can be streamed to, such as a file.
+type StatsWriter interface {
+	// Write writes a single record.
+	Write(GenerationStats) error
+}
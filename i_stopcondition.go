@@ -0,0 +1,10 @@
+package goevo
+
+// StopCondition is a criterion that decides when a [Runner] should stop evolving a population.
+type StopCondition interface {
+	// ShouldStop is called once per generation, after every agent in the generation has been evaluated.
+	// It returns true if the run should stop.
+	ShouldStop(summary RunSummary) bool
+	// String returns a short description of the condition, which is used as [RunSummary.StopReason].
+	String() string
+}
@@ -0,0 +1,11 @@
+package goevo
+
+// TemperatureSchedule is a strategy for controlling the temperature of simulated annealing, used by [HillClimberPopulation.WithAnnealing].
+// A worse candidate is accepted with probability exp(-loss / temperature), where loss is how much less fit it is.
+type TemperatureSchedule interface {
+	// Temperature returns the current temperature.
+	Temperature() float64
+	// Step advances the schedule after each candidate has been considered.
+	// It is told whether the candidate was accepted, for schedules that adapt to the acceptance rate.
+	Step(accepted bool)
+}
@@ -1,24 +1,69 @@
 package goevo
 
+import "io"
+
 // ================================== Utilities ==================================
 
 // Generators
-var _ Generator[float64] = NewGeneratorNormal(0.0, 0.0)
-var _ Generator[rune] = NewGeneratorChoices([]rune("abcdefg"))
+var _ Generator[float64] = NewGeneratorNormal(NewRand(0), 0.0, 0.0)
+var _ Generator[rune] = NewGeneratorChoices(NewRand(0), []rune("abcdefg"))
 
 // Reproductions
-var _ Reproduction[any] = &twoPhaseReproduction[any]{}
+var _ AgentReproduction[any] = &twoPhaseReproduction[any]{}
+
+// Codecs
+var _ Codec[*NeatGenotype] = NewJSONCodec[*NeatGenotype]()
+
+// Stats writers
+var _ StatsWriter = NewCSVStatsWriter(io.Discard)
+var _ StatsWriter = NewJSONStatsWriter(io.Discard)
+
+// Stop conditions
+var _ StopCondition = NewStopTargetFitness(0)
+var _ StopCondition = NewStopMaxGenerations(1)
+var _ StopCondition = NewStopMaxDuration(1)
+var _ StopCondition = NewStopStagnation(1)
+
+// Migration topologies
+var _ MigrationTopology = NewRingTopology()
+var _ MigrationTopology = NewFullyConnectedTopology()
+var _ MigrationTopology = NewCustomTopology(nil)
+
+// Temperature schedules
+var _ TemperatureSchedule = NewExponentialSchedule(1, 0.5)
+var _ TemperatureSchedule = NewLinearSchedule(1, 1)
+var _ TemperatureSchedule = NewAdaptiveSchedule(1, 0.5, 0.5, 1)
+
+// Scalable mutations
+var _ Scalable = &generatorNormal[float64]{}
+var _ Scalable = &twoPhaseReproduction[any]{}
+var _ Scalable = &arrayMutationGenerator[float64]{}
+var _ Scalable = &denseMutationUniform{}
+var _ Scalable = &neatMutationStd{}
+
+// Replacements
+var _ Replacement[any] = NewReplaceWorst[any]()
+var _ Replacement[any] = NewReplaceOldest[any]()
+var _ Replacement[any] = NewReplaceTournamentLoser[any](NewRand(0), 2)
+var _ Replacement[any] = NewReplaceIfBetter(NewReplaceWorst[any]())
+
+// Fitness adjustments
+var _ FitnessAdjustment[any] = NewFitnessSharing(func(a, b any) float64 { return 0 }, 1, 1)
+var _ FitnessAdjustment[any] = NewLinearScaling[any](2)
+var _ FitnessAdjustment[any] = NewSigmaTruncation[any](2)
+var _ FitnessAdjustment[any] = NewRankFitness[any]()
+var _ FitnessAdjustment[any] = NewSpeciesSharing(func(*Agent[any]) int { return 0 })
 
 // ================================== Genotypes ==================================
 
 // Array genotypes
 var _ Cloneable = &ArrayGenotype[int]{}
-var _ Crossover[*ArrayGenotype[any]] = NewArrayCrossoverUniform[any]()
+var _ Crossover[*ArrayGenotype[any]] = NewArrayCrossoverUniform[any](NewRand(0))
 var _ Crossover[*ArrayGenotype[any]] = NewArrayCrossoverAsexual[any]()
-var _ Crossover[*ArrayGenotype[any]] = NewArrayCrossoverKPoint[any](0)
-var _ Mutation[*ArrayGenotype[float64]] = NewArrayMutationGeneratorAdd(NewGeneratorNormal(0.0, 0.0), 0.0)
-var _ Mutation[*ArrayGenotype[bool]] = NewArrayMutationGeneratorReplace(NewGeneratorChoices([]bool{true, false}), 0.0)
-var _ Mutation[*ArrayGenotype[bool]] = NewArrayMutationGenerator(NewGeneratorChoices([]bool{true, false}), func(old, new bool) bool { return old && new }, 0.0)
+var _ Crossover[*ArrayGenotype[any]] = NewArrayCrossoverKPoint[any](NewRand(0), 0)
+var _ Mutation[*ArrayGenotype[float64]] = NewArrayMutationGeneratorAdd(NewGeneratorNormal(NewRand(0), 0.0, 0.0), 0.0)
+var _ Mutation[*ArrayGenotype[bool]] = NewArrayMutationGeneratorReplace(NewGeneratorChoices(NewRand(0), []bool{true, false}), 0.0)
+var _ Mutation[*ArrayGenotype[bool]] = NewArrayMutationGenerator(NewGeneratorChoices(NewRand(0), []bool{true, false}), func(old, new bool) bool { return old && new }, 0.0)
 
 // Dense genotypes
 var _ Cloneable = &DenseGenotype{}
@@ -32,7 +77,37 @@ var _ Buildable = &NeatGenotype{}
 var _ Forwarder = &NeatPhenotype{}
 var _ Crossover[*NeatGenotype] = &neatCrossoverSimple{}
 var _ Crossover[*NeatGenotype] = &neatCrossoverAsexual{}
+var _ AgentCrossover[*NeatGenotype] = &neatCrossoverInnovation{}
 var _ Mutation[*NeatGenotype] = &neatMutationStd{}
+var _ NeatInnovations = NewCounter()
+var _ NeatInnovations = NewNeatInnovationTracker(NewCounter())
+
+// Tree genotypes
+var _ Cloneable = &TreeGenotype{}
+var _ Validateable = &TreeGenotype{}
+var _ Forwarder = &TreeGenotype{}
+var _ Crossover[*TreeGenotype] = &treeCrossoverSubtree{}
+var _ Mutation[*TreeGenotype] = &treeMutationSubtree{}
+var _ Mutation[*TreeGenotype] = &treeMutationPoint{}
+var _ Mutation[*TreeGenotype] = &treeMutationHoist{}
+
+// CGP genotypes + phenotypes
+var _ Cloneable = &CGPGenotype{}
+var _ Validateable = &CGPGenotype{}
+var _ Buildable = &CGPGenotype{}
+var _ Forwarder = &CGPPhenotype{}
+var _ Crossover[*CGPGenotype] = &cgpCrossoverAsexual{}
+var _ Mutation[*CGPGenotype] = &cgpMutationPoint{}
+
+// Linear genotypes
+var _ Cloneable = &LinearGenotype{}
+var _ Validateable = &LinearGenotype{}
+var _ Buildable = &LinearGenotype{}
+var _ Forwarder = &LinearGenotype{}
+var _ Forwarder = &LinearPhenotype{}
+var _ Crossover[*LinearGenotype] = &linearCrossoverTwoPoint{}
+var _ Mutation[*LinearGenotype] = &linearMutationInstruction{}
+var _ Mutation[*LinearGenotype] = &linearMutationMacro{}
 
 // ================================== Selections ==================================
 
@@ -40,15 +115,53 @@ var _ Mutation[*NeatGenotype] = &neatMutationStd{}
 var _ Selection[any] = &eliteSelection[any]{}
 
 // Tournament selection
-var _ Selection[any] = NewTournamentSelection[any](3)
+var _ Selection[any] = NewTournamentSelection[any](NewRand(0), 3)
+
+// Novelty selection
+var _ Selection[any] = &noveltySelection[any]{}
+
+// Adjusted selection
+var _ Selection[any] = &adjustedSelection[any]{}
 
 // ================================== Populations ==================================
 
 // Simple population
-var _ Population[any] = &SimplePopulation[any]{}
+var _ IncrementalPopulation[any] = &SimplePopulation[any]{}
 
 // Speiated population
-var _ Population[any] = &SpeciatedPopulation[any]{}
+var _ IncrementalPopulation[any] = &SpeciatedPopulation[any]{}
 
 // Hill climber population
 var _ Population[any] = &HillClimberPopulation[any]{}
+
+// NSGA-II population
+var _ IncrementalPopulation[any] = &NSGA2Population[any]{}
+
+// MAP-Elites population
+var _ Population[any] = &MapElitesPopulation[any]{}
+
+// Island population
+var _ IncrementalPopulation[any] = &IslandPopulation[any]{}
+
+// Steady state population
+var _ IncrementalPopulation[any] = &SteadyStatePopulation[any]{}
+
+// CMA-ES population
+var _ Population[*ArrayGenotype[float64]] = &CMAESPopulation[float64]{}
+var _ Population[*ArrayGenotype[float32]] = &CMAESPopulation[float32]{}
+
+// Differential evolution population
+var _ IncrementalPopulation[*ArrayGenotype[float64]] = &DifferentialEvolutionPopulation[float64]{}
+
+// Evolution strategy population
+var _ IncrementalPopulation[*ArrayGenotype[float64]] = &EvolutionStrategyPopulation[float64]{}
+
+// ALPS population
+var _ IncrementalPopulation[any] = &ALPSPopulation[any]{}
+
+// Cellular population
+var _ IncrementalPopulation[any] = &CellularPopulation[any]{}
+
+// NEAT population
+var _ Population[*NeatGenotype] = &NeatPopulation{}
+var _ speciesPopulation[*NeatGenotype] = &NeatPopulation{}
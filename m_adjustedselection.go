@@ -0,0 +1,43 @@
+package goevo
+
+// adjustedSelection is a [Selection] that selects agents by their adjusted fitness, using another selection strategy.
+type adjustedSelection[T any] struct {
+	rescored    *rescoredSelection[T]
+	adjustments []FitnessAdjustment[T]
+}
+
+// NewAdjustedSelection creates a new [Selection] that applies each adjustment in turn to the fitness of the agents,
+// and then selects with the inner selection using the adjusted fitness.
+// This works with any selection, such as [NewTournamentSelection] or [NewEliteSelection].
+// The agents themselves are never modified, so their raw fitness is still available for reporting.
+func NewAdjustedSelection[T any](inner Selection[T], adjustments ...FitnessAdjustment[T]) Selection[T] {
+	for _, a := range adjustments {
+		if a == nil {
+			panic("cannot have nil adjustment")
+		}
+	}
+	return &adjustedSelection[T]{
+		rescored:    newRescoredSelection(inner),
+		adjustments: adjustments,
+	}
+}
+
+// SetAgents implements [Selection].
+func (s *adjustedSelection[T]) SetAgents(agents []*Agent[T]) {
+	scores := make([]float64, len(agents))
+	for i, a := range agents {
+		scores[i] = a.Fitness
+	}
+	for _, adj := range s.adjustments {
+		scores = adj.Adjust(agents, scores)
+		if len(scores) != len(agents) {
+			panic("fitness adjustment returned the wrong number of scores")
+		}
+	}
+	s.rescored.setAgents(agents, scores)
+}
+
+// Select implements [Selection].
+func (s *adjustedSelection[T]) Select() *Agent[T] {
+	return s.rescored.selectAgent()
+}
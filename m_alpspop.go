@@ -0,0 +1,251 @@
+package goevo
+
+import "slices"
+
+// ALPSPopulation is an age-layered population structure (ALPS), which avoids premature convergence by regularly introducing new genotypes.
+// The agents are split into layers by their [Agent.Age], and agents only compete with agents of a similar age,
+// so new genotypes have time to improve before they have to compete with the best agents found so far.
+//
+// Each generation, the age of every agent used as a parent increases by one in the next generation, and children are one older than their oldest parent.
+// An agent that becomes too old for its layer moves up to the next layer, replacing the worst agent there if it is fitter.
+// Every reseedInterval generations, the agents in the bottom layer move up in the same way, and it is replaced with new genotypes of age 0.
+type ALPSPopulation[T any] struct {
+	// layers are the agents in each layer, from youngest to oldest. Inactive layers are empty.
+	layers [][]*Agent[T]
+	// ageLimits is the maximum age of an agent in each layer, except the top layer which has no limit.
+	ageLimits []int
+	// numActive is the number of layers, from the bottom, that have had agents moved into them.
+	numActive int
+	// layerSize is the number of agents bred into each active layer.
+	layerSize int
+	// reseedInterval is the number of generations between each re-seeding of the bottom layer.
+	reseedInterval int
+	// numElites is the number of fittest agents of each layer copied into the next generation.
+	numElites int
+	// numCarried is the number of agents at the start of each layer that were copied from the previous generation as elites.
+	numCarried []int
+	// generation is the number of generations since the population was created.
+	generation   int
+	newGenotype  func() T
+	selection    Selection[T]
+	reproduction Reproduction[T]
+}
+
+// NewALPSPopulation creates a new [ALPSPopulation] with len(ageLimits)+1 layers of layerSize agents each.
+// The bottom layer starts with new genotypes, and the other layers are filled as agents grow too old for the layers below.
+//
+// The age limits must be increasing, and give the maximum age of an agent in each layer except the top.
+// They are usually a multiple of reseedInterval, for example with [PolynomialAgeLimits].
+// Parents for each layer are selected from that layer and the layer below, using the selection and reproduction strategies.
+// The numElites fittest agents of each layer are copied unchanged into the next generation, and are not evaluated again.
+func NewALPSPopulation[T any](newGenotype func() T, layerSize int, ageLimits []int, reseedInterval, numElites int, selection Selection[T], reproduction Reproduction[T]) *ALPSPopulation[T] {
+	if newGenotype == nil {
+		panic("cannot have nil newGenotype")
+	}
+	if layerSize <= 0 {
+		panic("cannot create layers with less than 1 member")
+	}
+	for i, l := range ageLimits {
+		if l < 0 || (i > 0 && l <= ageLimits[i-1]) {
+			panic("age limits must be increasing and at least 0")
+		}
+	}
+	if reseedInterval <= 0 {
+		panic("reseed interval must be at least 1")
+	}
+	if numElites < 0 || numElites >= layerSize {
+		panic("number of elites must be at least 0 and less than the layer size")
+	}
+	if selection == nil {
+		panic("cannot have nil selection")
+	}
+	if reproduction == nil {
+		panic("cannot have nil reproduction")
+	}
+	p := &ALPSPopulation[T]{
+		layers:         make([][]*Agent[T], len(ageLimits)+1),
+		ageLimits:      slices.Clone(ageLimits),
+		numActive:      1,
+		layerSize:      layerSize,
+		reseedInterval: reseedInterval,
+		numElites:      numElites,
+		numCarried:     make([]int, len(ageLimits)+1),
+		newGenotype:    newGenotype,
+		selection:      selection,
+		reproduction:   reproduction,
+	}
+	p.layers[0] = p.newLayer()
+	return p
+}
+
+// PolynomialAgeLimits returns the age limits of numLayers layers using the polynomial scheme from the original ALPS paper,
+// which is ageGap multiplied by 1, 2, 4, 9, 16, 25 and so on.
+// As the top layer has no limit, only numLayers-1 limits are returned.
+func PolynomialAgeLimits(ageGap, numLayers int) []int {
+	if ageGap <= 0 {
+		panic("age gap must be at least 1")
+	}
+	if numLayers <= 0 {
+		panic("must have at least one layer")
+	}
+	limits := make([]int, numLayers-1)
+	for i := range limits {
+		switch i {
+		case 0:
+			limits[i] = ageGap
+		case 1:
+			limits[i] = ageGap * 2
+		default:
+			limits[i] = ageGap * i * i
+		}
+	}
+	return limits
+}
+
+// newLayer creates a layer of new genotypes with age 0.
+func (p *ALPSPopulation[T]) newLayer() []*Agent[T] {
+	agents := make([]*Agent[T], p.layerSize)
+	for i := range agents {
+		agents[i] = NewAgent(p.newGenotype())
+	}
+	return agents
+}
+
+// NextGeneration implements [Population].
+// It first moves agents that are too old up a layer, then breeds each active layer, re-seeding the bottom layer if it is time to.
+func (p *ALPSPopulation[T]) NextGeneration() Population[T] {
+	generation := p.generation + 1
+	reseed := generation%p.reseedInterval == 0
+	layers := p.promote(reseed)
+	numActive := p.numActive
+	for numActive < len(layers) && len(layers[numActive]) > 0 {
+		numActive++
+	}
+
+	newLayers := make([][]*Agent[T], len(layers))
+	numCarried := make([]int, len(layers))
+	used := make(map[*Agent[T]]bool)
+	for l := range numActive {
+		if l == 0 && reseed {
+			continue
+		}
+		pool := slices.Clone(layers[l])
+		if l > 0 {
+			pool = append(pool, layers[l-1]...)
+		}
+		if len(pool) == 0 {
+			continue
+		}
+		p.selection.SetAgents(pool)
+		for range p.layerSize - min(p.numElites, len(layers[l])) {
+			parents := SelectN(p.selection, p.reproduction.NumParents())
+			child := NewAgent(ReproduceAgents(p.reproduction, parents))
+			for _, parent := range parents {
+				used[parent] = true
+				child.Age = max(child.Age, parent.Age+1)
+			}
+			newLayers[l] = append(newLayers[l], child)
+		}
+	}
+	for l := range numActive {
+		if l == 0 && reseed {
+			newLayers[l] = p.newLayer()
+			continue
+		}
+		// Elites that were used as parents are one generation older in the next generation, however many layers used them.
+		// The agents of this generation are copied rather than aged in place, so this population is left unchanged.
+		aged := make([]*Agent[T], len(layers[l]))
+		for i, a := range layers[l] {
+			aged[i] = a
+			if used[a] {
+				c := *a
+				c.Age++
+				aged[i] = &c
+			}
+		}
+		elites := copyElites(aged, p.numElites)
+		numCarried[l] = len(elites)
+		newLayers[l] = append(elites, newLayers[l]...)
+	}
+	return &ALPSPopulation[T]{
+		layers:         newLayers,
+		ageLimits:      p.ageLimits,
+		numActive:      numActive,
+		layerSize:      p.layerSize,
+		reseedInterval: p.reseedInterval,
+		numElites:      p.numElites,
+		numCarried:     numCarried,
+		generation:     generation,
+		newGenotype:    p.newGenotype,
+		selection:      p.selection,
+		reproduction:   p.reproduction,
+	}
+}
+
+// promote returns the layers after moving every agent that is older than the age limit of its layer up to the next layer.
+// If the bottom layer is about to be re-seeded, all of its agents are moved up, so that none of them are lost.
+// Layers are processed from the top down, so a layer has already lost its own old agents before it receives any.
+// A moved agent fills any free space in its new layer, and otherwise replaces the worst agent there if it is fitter.
+func (p *ALPSPopulation[T]) promote(reseed bool) [][]*Agent[T] {
+	layers := make([][]*Agent[T], len(p.layers))
+	for l := range layers {
+		layers[l] = slices.Clone(p.layers[l])
+	}
+	for l := len(p.ageLimits) - 1; l >= 0; l-- {
+		var stay, old []*Agent[T]
+		for _, a := range layers[l] {
+			if a.Age > p.ageLimits[l] || (l == 0 && reseed) {
+				old = append(old, a)
+			} else {
+				stay = append(stay, a)
+			}
+		}
+		layers[l] = stay
+		for _, a := range old {
+			if len(layers[l+1]) < p.layerSize {
+				layers[l+1] = append(layers[l+1], a)
+				continue
+			}
+			worst := 0
+			for i, b := range layers[l+1] {
+				if b.Fitness < layers[l+1][worst].Fitness {
+					worst = i
+				}
+			}
+			if a.Fitness > layers[l+1][worst].Fitness {
+				layers[l+1][worst] = a
+			}
+		}
+	}
+	return layers
+}
+
+// All returns every agent in the population, from the bottom layer to the top.
+func (p *ALPSPopulation[T]) All() []*Agent[T] {
+	return slices.Concat(p.layers...)
+}
+
+// Unevaluated implements [IncrementalPopulation].
+// It returns every agent except the elites copied from the last generation.
+func (p *ALPSPopulation[T]) Unevaluated() []*Agent[T] {
+	var agents []*Agent[T]
+	for l, layer := range p.layers {
+		agents = append(agents, layer[p.numCarried[l]:]...)
+	}
+	return agents
+}
+
+// Layers returns the agents in each layer, from the youngest layer to the oldest.
+// Layers that no agents have grown old enough to reach yet are empty.
+func (p *ALPSPopulation[T]) Layers() [][]*Agent[T] {
+	layers := make([][]*Agent[T], len(p.layers))
+	for l := range layers {
+		layers[l] = slices.Clone(p.layers[l])
+	}
+	return layers
+}
+
+// AgeLimits returns the maximum age of an agent in each layer, except the top layer which has no limit.
+func (p *ALPSPopulation[T]) AgeLimits() []int {
+	return slices.Clone(p.ageLimits)
+}
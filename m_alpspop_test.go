@@ -0,0 +1,63 @@
+package goevo
+
+import (
+	"math"
+	"testing"
+)
+
+// Check that agents are aged, moved up layers, and that the bottom layer is re-seeded
+func TestALPSPopulationLayers(t *testing.T) {
+	reprod := NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{})
+	ageLimits := PolynomialAgeLimits(3, 3)
+	assertEq(t, len(ageLimits), 2, "number of age limits")
+	assertEq(t, ageLimits[1], 6, "second age limit")
+	var pop Population[int] = NewALPSPopulation(func() int { return 0 }, 5, ageLimits, 3, 1, NewEliteSelection[int](), reprod)
+	assertEq(t, len(pop.All()), 5, "only bottom layer starts active")
+	assertEq(t, len(NextGeneration(pop.(*ALPSPopulation[int])).Unevaluated()), 4, "elites not re-evaluated")
+	for _, a := range pop.All() {
+		assertEq(t, a.Age, 0, "previous generation not aged")
+	}
+	for gen := 1; gen <= 20; gen++ {
+		for _, a := range pop.All() {
+			a.Fitness = float64(a.Genotype)
+		}
+		pop = pop.NextGeneration()
+		layers := pop.(*ALPSPopulation[int]).Layers()
+		assertEq(t, len(layers), 3, "number of layers")
+		if gen%3 == 0 {
+			for _, a := range layers[0] {
+				assertEq(t, a.Age, 0, "re-seeded age")
+			}
+		}
+		for l, layer := range layers[:2] {
+			for _, a := range layer {
+				// Children may be one generation older than the limit, until they are moved up next generation
+				if a.Age > ageLimits[l]+1 {
+					t.Fatalf("agent of age %v in layer %v with limit %v", a.Age, l, ageLimits[l])
+				}
+			}
+		}
+	}
+	layers := pop.(*ALPSPopulation[int]).Layers()
+	for _, layer := range layers {
+		assertEq(t, len(layer), 5, "active layer size")
+	}
+}
+
+// Check that an ALPS population can optimise a simple function
+func TestALPSPopulation(t *testing.T) {
+	rng := NewRand(0)
+	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0.0, 0.1), 0.5)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverUniform[float64](rng), mut)
+	pop := NewALPSPopulation(func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(5, NewGeneratorNormal(rng, 0.0, 1.0))
+	}, 20, PolynomialAgeLimits(5, 4), 5, 2, NewTournamentSelection[*ArrayGenotype[float64]](rng, 3), reprod)
+	fitness := func(g *ArrayGenotype[float64]) float64 {
+		total := 0.0
+		for i := range g.Len() {
+			total -= math.Abs(g.At(i) - 1)
+		}
+		return total
+	}
+	testWithFitnessFunc(t, fitness, pop)
+}
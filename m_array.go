@@ -49,10 +49,17 @@ func (g *ArrayGenotype[T]) Clone() any {
 // arrayCrossoverUniform is a crossover strategy that selects each gene from one of the parents with equal probability.
 // The location of a gene has no effect on the probability of it being selected from either parent.
 // It requires two parents.
-type arrayCrossoverUniform[T any] struct{}
+type arrayCrossoverUniform[T any] struct {
+	rng *rand.Rand
+}
 
-func NewArrayCrossoverUniform[T any]() Crossover[*ArrayGenotype[T]] {
-	return &arrayCrossoverUniform[T]{}
+func NewArrayCrossoverUniform[T any](rng *rand.Rand) Crossover[*ArrayGenotype[T]] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	return &arrayCrossoverUniform[T]{
+		rng: rng,
+	}
 }
 
 // Crossover implements CrossoverStrategy.
@@ -66,7 +73,7 @@ func (p *arrayCrossoverUniform[T]) Crossover(gs []*ArrayGenotype[T]) *ArrayGenot
 	}
 	child := make([]T, len(pa.values))
 	for i := range child {
-		if rand.Float64() < 0.5 {
+		if p.rng.Float64() < 0.5 {
 			child[i] = pa.values[i]
 		} else {
 			child[i] = pb.values[i]
@@ -104,15 +111,20 @@ func (p *arrayCrossoverAsexual[T]) NumParents() int {
 // arrayCrossoverKPoint is a crossover strategy that selects K locations in the genome to switch parents.
 // It requires two parents.
 type arrayCrossoverKPoint[T any] struct {
-	k int
+	rng *rand.Rand
+	k   int
 }
 
-func NewArrayCrossoverKPoint[T any](k int) Crossover[*ArrayGenotype[T]] {
+func NewArrayCrossoverKPoint[T any](rng *rand.Rand, k int) Crossover[*ArrayGenotype[T]] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
 	if k < 0 {
 		panic("k must be > 0")
 	}
 	return &arrayCrossoverKPoint[T]{
-		k: k,
+		rng: rng,
+		k:   k,
 	}
 }
 
@@ -126,11 +138,11 @@ func (p *arrayCrossoverKPoint[T]) Crossover(gs []*ArrayGenotype[T]) *ArrayGenoty
 	}
 	crossoverPoints := make([]int, p.k)
 	for i := 0; i < p.k; i++ {
-		crossoverPoints[i] = rand.N(len(pa.values))
+		crossoverPoints[i] = p.rng.IntN(len(pa.values))
 	}
 	sort.Ints(crossoverPoints)
 	child := make([]T, len(pa.values))
-	fromParentA := rand.Float64() < 0.5
+	fromParentA := p.rng.Float64() < 0.5
 	currentCrossoverPoint := 0
 	for i := range child {
 		if currentCrossoverPoint < len(crossoverPoints) && crossoverPoints[currentCrossoverPoint] == i {
@@ -193,3 +205,14 @@ func (m *arrayMutationGenerator[T]) Mutate(g *ArrayGenotype[T]) {
 		g.values[i] = m.combine(g.values[i], m.gen.Next())
 	}
 }
+
+// WithScaledStrength implements [Scalable] by scaling the generator, which must also be [Scalable].
+func (m *arrayMutationGenerator[T]) WithScaledStrength(factor float64) any {
+	gen, ok := m.gen.(Scalable)
+	if !ok {
+		panic("array mutation generator is not scalable")
+	}
+	c := *m
+	c.gen = gen.WithScaledStrength(factor).(Generator[T])
+	return &c
+}
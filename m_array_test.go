@@ -2,17 +2,18 @@ package goevo
 
 import (
 	"math"
+	"math/rand/v2"
 	"testing"
 )
 
-func setupArrayTestStuff[T any](mut Mutation[*ArrayGenotype[T]], newGenotype func() *ArrayGenotype[T], crsType int, selecType int) Population[*ArrayGenotype[T]] {
+func setupArrayTestStuff[T any](rng *rand.Rand, mut Mutation[*ArrayGenotype[T]], newGenotype func() *ArrayGenotype[T], crsType int, selecType int) Population[*ArrayGenotype[T]] {
 	counter := NewCounter()
 	var crs Crossover[*ArrayGenotype[T]]
 	switch crsType {
 	case 0:
-		crs = NewArrayCrossoverKPoint[T](2)
+		crs = NewArrayCrossoverKPoint[T](rng, 2)
 	case 1:
-		crs = NewArrayCrossoverUniform[T]()
+		crs = NewArrayCrossoverUniform[T](rng)
 	case 2:
 		crs = NewArrayCrossoverAsexual[T]()
 	}
@@ -20,7 +21,7 @@ func setupArrayTestStuff[T any](mut Mutation[*ArrayGenotype[T]], newGenotype fun
 	var selec Selection[*ArrayGenotype[T]]
 	switch selecType {
 	case 0:
-		selec = NewTournamentSelection[*ArrayGenotype[T]](3)
+		selec = NewTournamentSelection[*ArrayGenotype[T]](rng, 3)
 	case 1:
 		selec = NewEliteSelection[*ArrayGenotype[T]]()
 	}
@@ -35,6 +36,7 @@ func setupArrayTestStuff[T any](mut Mutation[*ArrayGenotype[T]], newGenotype fun
 		)
 	} else {
 		pop = NewSpeciatedPopulation(
+			rng,
 			counter,
 			newGenotype,
 			5,
@@ -49,11 +51,12 @@ func setupArrayTestStuff[T any](mut Mutation[*ArrayGenotype[T]], newGenotype fun
 }
 
 func TestArrayGenotype(t *testing.T) {
-	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(0, 0.05), 0.1)
+	rng := NewRand(0)
+	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0, 0.05), 0.1)
 	newGenotype := func() *ArrayGenotype[float64] {
-		return NewArrayGenotype(10, NewGeneratorNormal(0, 0.5))
+		return NewArrayGenotype(10, NewGeneratorNormal(rng, 0, 0.5))
 	}
-	pop := setupArrayTestStuff(mut, newGenotype, 0, 0)
+	pop := setupArrayTestStuff(rng, mut, newGenotype, 0, 0)
 	// Fitness is max (0) when all the numbers sum to 10
 	fitness := func(f *ArrayGenotype[float64]) float64 {
 		total := 0.0
@@ -66,11 +69,12 @@ func TestArrayGenotype(t *testing.T) {
 }
 
 func TestRuneGenotype(t *testing.T) {
+	rng := NewRand(0)
 	runeset := []rune("ab")
-	valueGen := NewGeneratorChoices(runeset)
+	valueGen := NewGeneratorChoices(rng, runeset)
 	mut := NewArrayMutationGeneratorReplace(valueGen, 0.1)
 	newGenotype := func() *ArrayGenotype[rune] { return NewArrayGenotype(10, valueGen) }
-	pop := setupArrayTestStuff(mut, newGenotype, 1, 0)
+	pop := setupArrayTestStuff(rng, mut, newGenotype, 1, 0)
 	// Fitness is max (0) when there are 10 'a's
 	fitness := func(f *ArrayGenotype[rune]) float64 {
 		total := 0.0
@@ -85,10 +89,11 @@ func TestRuneGenotype(t *testing.T) {
 }
 
 func TestBoolGenotype(t *testing.T) {
-	valueGen := NewGeneratorChoices([]bool{false, true})
+	rng := NewRand(0)
+	valueGen := NewGeneratorChoices(rng, []bool{false, true})
 	mut := NewArrayMutationGeneratorReplace(valueGen, 0.1)
 	newGenotype := func() *ArrayGenotype[bool] { return NewArrayGenotype(10, valueGen) }
-	pop := setupArrayTestStuff(mut, newGenotype, 2, 1)
+	pop := setupArrayTestStuff(rng, mut, newGenotype, 2, 1)
 	// Fitness is max (0) when there are 10 'true's
 	fitness := func(f *ArrayGenotype[bool]) float64 {
 		total := 0.0
@@ -0,0 +1,263 @@
+package goevo
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// CellularNeighbourhood is an enum representing the shape of the neighbourhood of a cell in a [CellularPopulation].
+type CellularNeighbourhood int
+
+const (
+	// VonNeumannNeighbourhood contains every cell within the radius in manhattan distance, so a radius of 1 is the cell and the 4 cells beside it.
+	VonNeumannNeighbourhood CellularNeighbourhood = iota
+	// MooreNeighbourhood contains every cell within the radius in both directions, so a radius of 1 is the 3x3 square around the cell.
+	MooreNeighbourhood
+)
+
+// String returns the name of the neighbourhood.
+func (n CellularNeighbourhood) String() string {
+	switch n {
+	case VonNeumannNeighbourhood:
+		return "von Neumann"
+	case MooreNeighbourhood:
+		return "Moore"
+	default:
+		panic(fmt.Sprintf("unknown cellular neighbourhood %d", n))
+	}
+}
+
+// CellularUpdate is an enum representing the order in which the cells of a [CellularPopulation] are updated.
+type CellularUpdate int
+
+const (
+	// CellularSynchronous updates every cell at once each generation, breeding from the grid as it was at the start of the generation.
+	CellularSynchronous CellularUpdate = iota
+	// CellularLineSweep updates one cell each generation, going through the grid row by row.
+	CellularLineSweep
+	// CellularRandomSweep updates one cell each generation, going through the grid in a new random order each time it is swept.
+	CellularRandomSweep
+)
+
+// String returns the name of the update order.
+func (u CellularUpdate) String() string {
+	switch u {
+	case CellularSynchronous:
+		return "synchronous"
+	case CellularLineSweep:
+		return "line sweep"
+	case CellularRandomSweep:
+		return "random sweep"
+	default:
+		panic(fmt.Sprintf("unknown cellular update %d", u))
+	}
+}
+
+// CellularPopulation is a cellular genetic algorithm, where each agent sits in a cell of a 2D grid that wraps around at the edges (a torus).
+// Each child is bred by selecting parents from the neighbourhood of its cell only, and may only replace an agent in that neighbourhood.
+// As good genotypes can only spread a little each generation, the population keeps its diversity for longer.
+//
+// With [CellularSynchronous], every cell breeds a child each generation, and all of the children replace agents of the previous grid at once.
+// With the asynchronous updates, only one cell breeds a child each generation, so later cells breed from a grid that already contains earlier children.
+// In both cases only the children need evaluating, so the population is an [IncrementalPopulation].
+type CellularPopulation[T any] struct {
+	rng    *rand.Rand
+	width  int
+	height int
+	update CellularUpdate
+	// grid is every agent on the grid, row by row.
+	grid []*Agent[T]
+	// neighbourhoods is the cells in the neighbourhood of each cell, with the cell itself first.
+	neighbourhoods [][]int
+	// children are the children waiting to be evaluated, and childCells the cells that bred them.
+	// They are nil in the first generation, when the grid itself is unevaluated.
+	children   []*Agent[T]
+	childCells []int
+	// order is the order of the cells in the current sweep, and position the number of them that have been updated.
+	// They are only used by the asynchronous updates.
+	order    []int
+	position int
+
+	selection    Selection[T]
+	reproduction Reproduction[T]
+	replacement  Replacement[T]
+}
+
+// NewCellularPopulation creates a new [CellularPopulation] on a width by height grid, with a new genotype created by newGenotype in every cell.
+// Each neighbourhood contains the cells within radius of a cell (including itself) with the given shape.
+// The selection is given the agents in the neighbourhood of a cell to select the parents of its child.
+// The replacement is given the agents in the neighbourhood, with the cell itself first, to choose which one the evaluated child replaces.
+// For example, NewReplaceIfBetter(NewReplaceOldest()) replaces the cell itself if the child is fitter, which is the usual choice.
+func NewCellularPopulation[T any](
+	rng *rand.Rand,
+	newGenotype func() T,
+	width, height int,
+	neighbourhood CellularNeighbourhood,
+	radius int,
+	update CellularUpdate,
+	selection Selection[T],
+	reproduction Reproduction[T],
+	replacement Replacement[T],
+) *CellularPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if width <= 0 || height <= 0 {
+		panic("grid must be at least 1x1")
+	}
+	if radius < 0 {
+		panic("cannot have negative radius")
+	}
+	if selection == nil {
+		panic("cannot have nil selection")
+	}
+	if reproduction == nil {
+		panic("cannot have nil reproduction")
+	}
+	if replacement == nil {
+		panic("cannot have nil replacement")
+	}
+	if neighbourhood < VonNeumannNeighbourhood || neighbourhood > MooreNeighbourhood {
+		panic("unknown cellular neighbourhood")
+	}
+	if update < CellularSynchronous || update > CellularRandomSweep {
+		panic("unknown cellular update")
+	}
+	grid := make([]*Agent[T], width*height)
+	for i := range grid {
+		grid[i] = NewAgent(newGenotype())
+	}
+	return &CellularPopulation[T]{
+		rng:            rng,
+		width:          width,
+		height:         height,
+		update:         update,
+		grid:           grid,
+		neighbourhoods: cellularNeighbourhoods(width, height, neighbourhood, radius),
+		selection:      selection,
+		reproduction:   reproduction,
+		replacement:    replacement,
+	}
+}
+
+// cellularNeighbourhoods returns the cells in the neighbourhood of each cell of a width by height torus, with the cell itself first.
+// If the radius is large compared to the grid, each cell is still only included once.
+func cellularNeighbourhoods(width, height int, neighbourhood CellularNeighbourhood, radius int) [][]int {
+	neighbourhoods := make([][]int, width*height)
+	for y := range height {
+		for x := range width {
+			cell := y*width + x
+			cells := []int{cell}
+			for dy := -radius; dy <= radius; dy++ {
+				for dx := -radius; dx <= radius; dx++ {
+					if neighbourhood == VonNeumannNeighbourhood && abs(dx)+abs(dy) > radius {
+						continue
+					}
+					nx, ny := (x+dx%width+width)%width, (y+dy%height+height)%height
+					if n := ny*width + nx; !slices.Contains(cells, n) {
+						cells = append(cells, n)
+					}
+				}
+			}
+			neighbourhoods[cell] = cells
+		}
+	}
+	return neighbourhoods
+}
+
+// NextGeneration implements [Population].
+// Each evaluated child replaces the agent chosen by the replacement strategy, then new children are bred for the next cells to update.
+// Every replacement is chosen using the current grid before any are made, and if two children replace the same cell the fitter one is kept.
+func (p *CellularPopulation[T]) NextGeneration() Population[T] {
+	next := *p
+	next.grid = slices.Clone(p.grid)
+	replacements := make(map[int]*Agent[T])
+	for i, child := range p.children {
+		cells := p.neighbourhoods[p.childCells[i]]
+		r := p.replacement.Replace(p.agentsAt(p.grid, cells), child)
+		if r < 0 {
+			continue
+		}
+		if other, ok := replacements[cells[r]]; !ok || child.Fitness > other.Fitness {
+			replacements[cells[r]] = child
+		}
+	}
+	for cell, child := range replacements {
+		next.grid[cell] = child
+	}
+	var cells []int
+	if p.update == CellularSynchronous {
+		cells = make([]int, len(p.grid))
+		for i := range cells {
+			cells[i] = i
+		}
+	} else {
+		if next.order == nil || next.position == len(next.order) {
+			next.order, next.position = p.sweepOrder(), 0
+		}
+		cells = []int{next.order[next.position]}
+		next.position++
+	}
+	next.children = make([]*Agent[T], len(cells))
+	next.childCells = cells
+	for i, cell := range cells {
+		p.selection.SetAgents(p.agentsAt(next.grid, p.neighbourhoods[cell]))
+		parents := SelectN(p.selection, p.reproduction.NumParents())
+		next.children[i] = NewAgent(ReproduceAgents(p.reproduction, parents))
+	}
+	return &next
+}
+
+// sweepOrder returns the order to update the cells in for the next asynchronous sweep.
+func (p *CellularPopulation[T]) sweepOrder() []int {
+	if p.update == CellularRandomSweep {
+		return p.rng.Perm(len(p.grid))
+	}
+	order := make([]int, len(p.grid))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// agentsAt returns the agents in the given cells of the grid.
+func (p *CellularPopulation[T]) agentsAt(grid []*Agent[T], cells []int) []*Agent[T] {
+	agents := make([]*Agent[T], len(cells))
+	for i, c := range cells {
+		agents[i] = grid[c]
+	}
+	return agents
+}
+
+// All implements [Population].
+// It returns the agents on the grid row by row, followed by the children waiting to be evaluated.
+func (p *CellularPopulation[T]) All() []*Agent[T] {
+	return append(slices.Clone(p.grid), p.children...)
+}
+
+// Unevaluated implements [IncrementalPopulation].
+// It returns the children, or the whole grid in the first generation.
+func (p *CellularPopulation[T]) Unevaluated() []*Agent[T] {
+	if p.children == nil {
+		return p.grid
+	}
+	return p.children
+}
+
+// Grid returns the agents on the grid, indexed by [y][x].
+func (p *CellularPopulation[T]) Grid() [][]*Agent[T] {
+	rows := make([][]*Agent[T], p.height)
+	for y := range rows {
+		rows[y] = slices.Clone(p.grid[y*p.width : (y+1)*p.width])
+	}
+	return rows
+}
+
+// Neighbourhood returns the agents in the neighbourhood of the cell at x, y, with the agent in that cell first.
+func (p *CellularPopulation[T]) Neighbourhood(x, y int) []*Agent[T] {
+	if x < 0 || x >= p.width || y < 0 || y >= p.height {
+		panic("cell is outside of the grid")
+	}
+	return p.agentsAt(p.grid, p.neighbourhoods[y*p.width+x])
+}
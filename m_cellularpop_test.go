@@ -0,0 +1,91 @@
+package goevo
+
+import "testing"
+
+// Check the size of each neighbourhood, and that they wrap around the edges of the grid
+func TestCellularNeighbourhoods(t *testing.T) {
+	reprod := NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{})
+	counter := 0
+	newPop := func(width, height int, neighbourhood CellularNeighbourhood, radius int) *CellularPopulation[int] {
+		counter = 0
+		return NewCellularPopulation(NewRand(0), func() int { counter++; return counter - 1 }, width, height, neighbourhood, radius,
+			CellularSynchronous, NewEliteSelection[int](), reprod, NewReplaceIfBetter(NewReplaceOldest[int]()))
+	}
+	assertEq(t, len(newPop(5, 5, VonNeumannNeighbourhood, 1).Neighbourhood(2, 2)), 5, "von Neumann radius 1")
+	assertEq(t, len(newPop(5, 5, VonNeumannNeighbourhood, 2).Neighbourhood(2, 2)), 13, "von Neumann radius 2")
+	assertEq(t, len(newPop(5, 5, MooreNeighbourhood, 1).Neighbourhood(2, 2)), 9, "Moore radius 1")
+	assertEq(t, len(newPop(3, 3, MooreNeighbourhood, 2).Neighbourhood(1, 1)), 9, "Moore radius larger than grid")
+
+	pop := newPop(5, 4, VonNeumannNeighbourhood, 1)
+	genotypes := make(map[int]bool)
+	for _, a := range pop.Neighbourhood(0, 0) {
+		genotypes[a.Genotype] = true
+	}
+	assertEq(t, pop.Neighbourhood(0, 0)[0].Genotype, 0, "cell first")
+	assertEq(t, pop.Grid()[3][4].Genotype, 19, "grid indexed by y then x")
+	for _, g := range []int{1, 4, 5, 15} {
+		assertEq(t, genotypes[g], true, "neighbour wraps around")
+	}
+}
+
+// Check that children only replace agents in their neighbourhood, and how many children each update order breeds
+func TestCellularPopulationUpdates(t *testing.T) {
+	reprod := NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{})
+	for _, update := range []CellularUpdate{CellularSynchronous, CellularLineSweep, CellularRandomSweep} {
+		counter := 0
+		var pop Population[int] = NewCellularPopulation(NewRand(0), func() int { counter++; return counter - 1 }, 6, 6,
+			MooreNeighbourhood, 1, update, NewEliteSelection[int](), reprod, NewReplaceIfBetter(NewReplaceOldest[int]()))
+		for gen := range 3 {
+			for _, a := range pop.(*CellularPopulation[int]).Unevaluated() {
+				a.Fitness = float64(a.Genotype)
+			}
+			pop = pop.NextGeneration()
+			children := pop.(*CellularPopulation[int]).Unevaluated()
+			if update == CellularSynchronous {
+				assertEq(t, len(children), 36, "synchronous children")
+			} else {
+				assertEq(t, len(children), 1, update.String()+" children")
+			}
+			if update == CellularSynchronous && gen == 0 {
+				// Each cell breeds from the fittest agent in its neighbourhood, so the bottom right cell can see the top left corner
+				assertEq(t, children[35].Genotype, 35, "child of bottom right")
+				assertEq(t, children[0].Genotype, 35, "child of top left")
+				assertEq(t, children[14].Genotype, 21, "child of middle")
+			}
+		}
+	}
+}
+
+// Check that synchronous replacements are all chosen using the previous grid, keeping the fittest child when they replace the same cell
+func TestCellularPopulationSynchronousReplacement(t *testing.T) {
+	reprod := NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{})
+	counter := 0
+	// Every neighbourhood of a 3x1 grid contains every cell
+	pop := NewCellularPopulation(NewRand(0), func() int { counter++; return counter - 1 }, 3, 1,
+		MooreNeighbourhood, 1, CellularSynchronous, NewEliteSelection[int](), reprod, NewReplaceWorst[int]())
+	for _, a := range pop.Unevaluated() {
+		a.Fitness = float64(a.Genotype)
+	}
+	pop.children = []*Agent[int]{{Genotype: 5, Fitness: 5}, {Genotype: 7, Fitness: 7}, {Genotype: 6, Fitness: 6}}
+	pop.childCells = []int{0, 1, 2}
+	grid := NextGeneration(pop).Grid()
+	assertEq(t, grid[0][0].Genotype, 7, "fittest child replaces the worst cell")
+	assertEq(t, grid[0][1].Genotype, 1, "second cell unchanged")
+	assertEq(t, grid[0][2].Genotype, 2, "third cell unchanged")
+}
+
+// Check that a cellular population can optimise a simple function with each update order
+func TestCellularPopulation(t *testing.T) {
+	for _, update := range []CellularUpdate{CellularSynchronous, CellularLineSweep, CellularRandomSweep} {
+		t.Run(update.String(), func(t *testing.T) {
+			rng := NewRand(0)
+			mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0.0, 0.1), 0.5)
+			reprod := NewTwoPhaseReproduction(NewArrayCrossoverUniform[float64](rng), mut)
+			pop := NewCellularPopulation(rng, func() *ArrayGenotype[float64] {
+				return NewArrayGenotype(5, NewGeneratorNormal(rng, 0.0, 1.0))
+			}, 8, 8, VonNeumannNeighbourhood, 1, update, NewTournamentSelection[*ArrayGenotype[float64]](rng, 2), reprod,
+				NewReplaceIfBetter(NewReplaceOldest[*ArrayGenotype[float64]]()))
+			testWithFitnessFunc(t, distanceToOnesFitness, pop)
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package goevo
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// CGPGenotype is a genotype for Cartesian genetic programming (CGP), where the genotype is a fixed grid of nodes encoded as integers.
+// Each node has a function gene, which is an index into a list of [Activation]s, and a fixed number of connection genes.
+// The output of a node is its activation applied to the sum of the values it is connected to.
+// There is also one gene for each output, which is the value that output is connected to.
+//
+// Values are addressed with the inputs first, followed by the nodes column by column.
+// A node may connect to any input, or to any node in the levelsBack columns before its own, so the network is always feed-forward.
+// Outputs may connect to any input or node.
+//
+// Usually many nodes are not connected to any output. These inactive nodes have no effect, but can be changed freely by mutation,
+// which lets the genotype drift between equally fit networks. Use [CGPGenotype.Build] to create a network that only evaluates the active nodes.
+type CGPGenotype struct {
+	numInputs  int
+	numOutputs int
+	rows       int
+	columns    int
+	levelsBack int
+	arity      int
+	functions  []Activation
+	// genes are the genes of each node (function, then connections) column by column, followed by the output genes.
+	genes []int
+}
+
+// NewCGPGenotype creates a new [CGPGenotype] with random genes.
+// The grid has rows*columns nodes, each of which has arity connections and uses one of the given activations as its function.
+// A levelsBack of columns allows any feed-forward connection.
+func NewCGPGenotype(rng *rand.Rand, numInputs, numOutputs, rows, columns, levelsBack, arity int, functions []Activation) *CGPGenotype {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if numInputs <= 0 || numOutputs <= 0 {
+		panic("must have at least one input and one output")
+	}
+	if rows <= 0 || columns <= 0 {
+		panic("grid must be at least 1x1")
+	}
+	if levelsBack <= 0 {
+		panic("levels back must be at least 1")
+	}
+	if arity <= 0 {
+		panic("arity must be at least 1")
+	}
+	if len(functions) == 0 {
+		panic("must have at least one function")
+	}
+	for _, f := range functions {
+		if !f.CanSingleApply() {
+			panic(fmt.Sprintf("the activation '%s' cannot be used as a cgp function", f))
+		}
+	}
+	g := &CGPGenotype{
+		numInputs:  numInputs,
+		numOutputs: numOutputs,
+		rows:       rows,
+		columns:    columns,
+		levelsBack: levelsBack,
+		arity:      arity,
+		functions:  slices.Clone(functions),
+		genes:      make([]int, rows*columns*(arity+1)+numOutputs),
+	}
+	for i := range g.genes {
+		g.genes[i] = g.randomGene(rng, i)
+	}
+	return g
+}
+
+// numNodes returns the number of nodes in the grid.
+func (g *CGPGenotype) numNodes() int {
+	return g.rows * g.columns
+}
+
+// connectionRange returns the addresses that the gene at index i can connect to, as the number of addresses,
+// and the first address after the inputs. If i is a function gene, it returns 0 addresses.
+func (g *CGPGenotype) connectionRange(i int) (int, int) {
+	nodeGenes := g.numNodes() * (g.arity + 1)
+	if i >= nodeGenes {
+		return g.numInputs + g.numNodes(), g.numInputs
+	}
+	if i%(g.arity+1) == 0 {
+		return 0, 0
+	}
+	column := i / (g.arity + 1) / g.rows
+	firstColumn := max(0, column-g.levelsBack)
+	return g.numInputs + (column-firstColumn)*g.rows, g.numInputs + firstColumn*g.rows
+}
+
+// randomGene returns a new random valid value for the gene at index i.
+func (g *CGPGenotype) randomGene(rng *rand.Rand, i int) int {
+	n, firstNode := g.connectionRange(i)
+	if n == 0 {
+		return rng.IntN(len(g.functions))
+	}
+	a := rng.IntN(n)
+	if a < g.numInputs {
+		return a
+	}
+	return firstNode + a - g.numInputs
+}
+
+// activeNodes returns whether each node is connected (directly or indirectly) to an output.
+func (g *CGPGenotype) activeNodes() []bool {
+	active := make([]bool, g.numNodes())
+	nodeGenes := g.numNodes() * (g.arity + 1)
+	for _, a := range g.genes[nodeGenes:] {
+		if a >= g.numInputs {
+			active[a-g.numInputs] = true
+		}
+	}
+	// Nodes only connect backwards, so going backwards finds every active node in one pass
+	for n := g.numNodes() - 1; n >= 0; n-- {
+		if !active[n] {
+			continue
+		}
+		for _, a := range g.genes[n*(g.arity+1)+1 : (n+1)*(g.arity+1)] {
+			if a >= g.numInputs {
+				active[a-g.numInputs] = true
+			}
+		}
+	}
+	return active
+}
+
+// isActiveGene returns whether the gene at index i affects the output, which is true for output genes and the genes of active nodes.
+func (g *CGPGenotype) isActiveGene(active []bool, i int) bool {
+	n := i / (g.arity + 1)
+	return n >= g.numNodes() || active[n]
+}
+
+// ActiveNodes returns the index of every node that is connected to an output, in the order they are evaluated.
+// Node i is at row i%rows of column i/rows.
+func (g *CGPGenotype) ActiveNodes() []int {
+	var nodes []int
+	for n, a := range g.activeNodes() {
+		if a {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Clone implements [Cloneable].
+func (g *CGPGenotype) Clone() any {
+	c := *g
+	c.genes = slices.Clone(g.genes)
+	return &c
+}
+
+// Validate implements [Validateable].
+// It checks that every gene is a valid function or respects the levels back.
+func (g *CGPGenotype) Validate() error {
+	if len(g.genes) != g.numNodes()*(g.arity+1)+g.numOutputs {
+		return fmt.Errorf("genotype has %v genes, expected %v", len(g.genes), g.numNodes()*(g.arity+1)+g.numOutputs)
+	}
+	for i, v := range g.genes {
+		n, firstNode := g.connectionRange(i)
+		if n == 0 {
+			if v < 0 || v >= len(g.functions) {
+				return fmt.Errorf("gene %v has invalid function %v", i, v)
+			}
+			continue
+		}
+		if v < 0 || (v >= g.numInputs && (v < firstNode || v >= firstNode+n-g.numInputs)) {
+			return fmt.Errorf("gene %v has invalid connection %v", i, v)
+		}
+	}
+	return nil
+}
+
+// Build implements [Buildable], creating a network that only evaluates the active nodes.
+func (g *CGPGenotype) Build() Forwarder {
+	p := &CGPPhenotype{
+		numInputs: g.numInputs,
+		values:    make([]float64, g.numInputs+g.numNodes()),
+		outputs:   slices.Clone(g.genes[g.numNodes()*(g.arity+1):]),
+	}
+	for _, n := range g.ActiveNodes() {
+		genes := g.genes[n*(g.arity+1) : (n+1)*(g.arity+1)]
+		p.nodes = append(p.nodes, cgpPhenotypeNode{
+			address:    g.numInputs + n,
+			activation: g.functions[genes[0]],
+			inputs:     slices.Clone(genes[1:]),
+		})
+	}
+	return p
+}
+
+// cgpPhenotypeNode is an active node of a [CGPPhenotype].
+type cgpPhenotypeNode struct {
+	address    int
+	activation Activation
+	inputs     []int
+}
+
+// CGPPhenotype is a network built from a [CGPGenotype], which only contains the active nodes.
+type CGPPhenotype struct {
+	numInputs int
+	// values is the value at every address of the genotype. Values of inactive nodes are never set.
+	values  []float64
+	nodes   []cgpPhenotypeNode
+	outputs []int
+}
+
+// Forward implements [Forwarder].
+func (p *CGPPhenotype) Forward(x []float64) []float64 {
+	if len(x) != p.numInputs {
+		panic("incorrect number of inputs")
+	}
+	copy(p.values, x)
+	for _, n := range p.nodes {
+		total := 0.0
+		for _, a := range n.inputs {
+			total += p.values[a]
+		}
+		p.values[n.address] = n.activation.ActivateValue(total)
+	}
+	outs := make([]float64, len(p.outputs))
+	for i, a := range p.outputs {
+		outs[i] = p.values[a]
+	}
+	return outs
+}
+
+// cgpMutationPoint is a mutation that changes random genes of a [CGPGenotype] to new random valid values.
+type cgpMutationPoint struct {
+	rng          *rand.Rand
+	chance       float64
+	singleActive bool
+}
+
+// NewCGPMutationPoint creates a new point mutation for [CGPGenotype]s, where each gene is set to a new random valid value with the given chance.
+func NewCGPMutationPoint(rng *rand.Rand, chance float64) Mutation[*CGPGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if chance < 0 || chance > 1 {
+		panic("cannot have chance out of range 0-1")
+	}
+	return &cgpMutationPoint{
+		rng:    rng,
+		chance: chance,
+	}
+}
+
+// NewCGPMutationSingleActive creates a new point mutation for [CGPGenotype]s that changes random genes until exactly one active gene has changed.
+// Unlike [NewCGPMutationPoint], this never wastes an evaluation on a child that behaves exactly like its parent.
+func NewCGPMutationSingleActive(rng *rand.Rand) Mutation[*CGPGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	return &cgpMutationPoint{
+		rng:          rng,
+		singleActive: true,
+	}
+}
+
+// Mutate implements [Mutation].
+func (m *cgpMutationPoint) Mutate(g *CGPGenotype) {
+	if !m.singleActive {
+		for i := range g.genes {
+			if m.rng.Float64() < m.chance {
+				g.genes[i] = g.randomGene(m.rng, i)
+			}
+		}
+		return
+	}
+	active := g.activeNodes()
+	for {
+		i := m.rng.IntN(len(g.genes))
+		old := g.genes[i]
+		g.genes[i] = g.randomGene(m.rng, i)
+		if g.genes[i] != old && g.isActiveGene(active, i) {
+			return
+		}
+	}
+}
+
+// cgpCrossoverAsexual is a crossover that clones its only parent. CGP does not usually use crossover.
+type cgpCrossoverAsexual struct{}
+
+// NewCGPCrossoverAsexual creates a new crossover for [CGPGenotype]s that clones its only parent.
+func NewCGPCrossoverAsexual() Crossover[*CGPGenotype] {
+	return &cgpCrossoverAsexual{}
+}
+
+// Crossover implements [Crossover].
+func (c *cgpCrossoverAsexual) Crossover(gs []*CGPGenotype) *CGPGenotype {
+	if len(gs) != 1 {
+		panic("asexual crossover requires exactly 1 parent")
+	}
+	return Clone(gs[0])
+}
+
+// NumParents implements [Crossover].
+func (c *cgpCrossoverAsexual) NumParents() int {
+	return 1
+}
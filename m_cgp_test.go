@@ -0,0 +1,75 @@
+package goevo
+
+import (
+	"slices"
+	"testing"
+)
+
+// Check that only the active nodes are evaluated, and that genes are validated against the levels back
+func TestCGPGenotype(t *testing.T) {
+	// 2 inputs, 1 row of 3 nodes with 2 connections each, and 1 output
+	g := NewCGPGenotype(NewRand(0), 2, 1, 1, 3, 1, 2, []Activation{Linear, Relu})
+	g.genes = []int{
+		0, 0, 1, // node 0 (address 2): x0 + x1
+		1, 2, 0, // node 1 (address 3): relu(node 0 + x0)
+		0, 3, 3, // node 2 (address 4): node 1 + node 1, which is not connected to the output
+		3, // the output is node 1
+	}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, slices.Equal(g.ActiveNodes(), []int{0, 1}), true, "active nodes")
+	net := g.Build()
+	assertEq(t, net.Forward([]float64{1, 2})[0], 4.0, "forward")
+	assertEq(t, net.Forward([]float64{-3, 1})[0], 0.0, "forward with relu")
+	assertEq(t, len(net.(*CGPPhenotype).nodes), 2, "only active nodes are compiled")
+
+	c := Clone(g)
+	// Node 2 is in column 2, so with a levels back of 1 it cannot connect to node 0 in column 0
+	c.genes[7] = 2
+	if c.Validate() == nil {
+		t.Fatal("connection beyond levels back was valid")
+	}
+	assertEq(t, g.genes[7], 3, "clone is deep")
+}
+
+// Check that random genes and mutations are always valid, and that the single active mutation changes exactly one active gene
+func TestCGPMutation(t *testing.T) {
+	rng := NewRand(0)
+	point := NewCGPMutationPoint(rng, 0.1)
+	singleActive := NewCGPMutationSingleActive(rng)
+	for range 200 {
+		g := NewCGPGenotype(rng, 3, 2, 2, 5, 2, 2, AllSingleActivations)
+		if err := g.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		c := Clone(g)
+		point.Mutate(c)
+		if err := c.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		c = Clone(g)
+		singleActive.Mutate(c)
+		if err := c.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		active := g.activeNodes()
+		changedActive := 0
+		for i := range g.genes {
+			if g.genes[i] != c.genes[i] && g.isActiveGene(active, i) {
+				changedActive++
+			}
+		}
+		assertEq(t, changedActive, 1, "changed active genes")
+	}
+}
+
+func TestCGPXOR(t *testing.T) {
+	rng := NewRand(0)
+	functions := []Activation{Linear, Relu, Binary, Cos, Gaussian, Abs}
+	reprod := NewTwoPhaseReproduction(NewCGPCrossoverAsexual(), NewCGPMutationSingleActive(rng))
+	pop := NewSimplePopulation(func() *CGPGenotype {
+		return NewCGPGenotype(rng, 3, 1, 1, 20, 20, 2, functions)
+	}, 50, NewTournamentSelection[*CGPGenotype](rng, 3), reprod).WithElitism(1, false)
+	testWithXORDataset(t, Population[*CGPGenotype](pop), nil)
+}
@@ -0,0 +1,234 @@
+package goevo
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CMAESPopulation is a population that uses the covariance matrix adaptation evolution strategy (CMA-ES)
+// to optimise a vector of floats. It works well on continuous problems that are badly conditioned or have correlated parameters.
+//
+// Each generation samples lambda agents from a multivariate normal distribution.
+// Once evaluated, the fittest half are used to move the mean of the distribution,
+// and to adapt its covariance matrix and step size using the cumulative evolution paths.
+// It does not use a [Selection] or [Reproduction], as the whole algorithm works on the distribution.
+type CMAESPopulation[T floatType] struct {
+	rng        *rand.Rand
+	agents     []*Agent[*ArrayGenotype[T]]
+	generation int
+	// Strategy parameters, which are fixed for a run
+	params *cmaesParams
+	// State of the distribution
+	mean  *mat.VecDense
+	sigma float64
+	cov   *mat.SymDense
+	pathC *mat.VecDense
+	pathS *mat.VecDense
+	// Eigendecomposition of cov, so that cov = basis * diag(scales^2) * basis^T
+	basis  *mat.Dense
+	scales []float64
+}
+
+// cmaesParams are the constants of a CMA-ES run, which depend only on the number of dimensions and lambda.
+type cmaesParams struct {
+	n, lambda, mu  int
+	weights        []float64
+	muEff          float64
+	cc, cs, c1, cm float64
+	damps          float64
+	chiN           float64
+}
+
+// NewCMAESPopulation creates a new CMAESPopulation, with the search distribution centred on mean with a step size of sigma.
+// Each generation will contain lambda agents. A good default for lambda is 4 + floor(3 ln n), where n is the length of mean.
+func NewCMAESPopulation[T floatType](rng *rand.Rand, mean []float64, sigma float64, lambda int) *CMAESPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if len(mean) == 0 {
+		panic("must have at least one dimension")
+	}
+	if sigma <= 0 {
+		panic("must have a step size greater than 0")
+	}
+	if lambda < 2 {
+		panic("must have lambda of at least 2")
+	}
+	n := len(mean)
+	cov := mat.NewSymDense(n, nil)
+	basis := mat.NewDense(n, n, nil)
+	scales := make([]float64, n)
+	for i := range n {
+		cov.SetSym(i, i, 1)
+		basis.Set(i, i, 1)
+		scales[i] = 1
+	}
+	p := &CMAESPopulation[T]{
+		rng:    rng,
+		params: newCMAESParams(n, lambda),
+		mean:   mat.NewVecDense(n, slices.Clone(mean)),
+		sigma:  sigma,
+		cov:    cov,
+		pathC:  mat.NewVecDense(n, nil),
+		pathS:  mat.NewVecDense(n, nil),
+		basis:  basis,
+		scales: scales,
+	}
+	p.agents = p.sample()
+	return p
+}
+
+// newCMAESParams calculates the default strategy parameters from Hansen's CMA-ES tutorial.
+func newCMAESParams(n, lambda int) *cmaesParams {
+	mu := lambda / 2
+	weights := make([]float64, mu)
+	total := 0.0
+	for i := range weights {
+		weights[i] = math.Log(float64(mu)+0.5) - math.Log(float64(i+1))
+		total += weights[i]
+	}
+	sumSq := 0.0
+	for i := range weights {
+		weights[i] /= total
+		sumSq += weights[i] * weights[i]
+	}
+	muEff := 1 / sumSq
+	nf := float64(n)
+	cs := (muEff + 2) / (nf + muEff + 5)
+	c1 := 2 / ((nf+1.3)*(nf+1.3) + muEff)
+	return &cmaesParams{
+		n:       n,
+		lambda:  lambda,
+		mu:      mu,
+		weights: weights,
+		muEff:   muEff,
+		cc:      (4 + muEff/nf) / (nf + 4 + 2*muEff/nf),
+		cs:      cs,
+		c1:      c1,
+		cm:      math.Min(1-c1, 2*(muEff-2+1/muEff)/((nf+2)*(nf+2)+muEff)),
+		damps:   1 + 2*math.Max(0, math.Sqrt((muEff-1)/(nf+1))-1) + cs,
+		chiN:    math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf)),
+	}
+}
+
+// sample draws lambda new agents from the current distribution.
+func (p *CMAESPopulation[T]) sample() []*Agent[*ArrayGenotype[T]] {
+	n := p.params.n
+	agents := make([]*Agent[*ArrayGenotype[T]], p.params.lambda)
+	z := mat.NewVecDense(n, nil)
+	y := mat.NewVecDense(n, nil)
+	for i := range agents {
+		for j := range n {
+			z.SetVec(j, p.scales[j]*p.rng.NormFloat64())
+		}
+		y.MulVec(p.basis, z)
+		values := make([]T, n)
+		for j := range values {
+			values[j] = T(p.mean.AtVec(j) + p.sigma*y.AtVec(j))
+		}
+		agents[i] = NewAgent(&ArrayGenotype[T]{values: values})
+	}
+	return agents
+}
+
+// NextGeneration implements [Population].
+// It updates the distribution using the fitnesses of the current agents, then samples a new set of agents.
+func (p *CMAESPopulation[T]) NextGeneration() Population[*ArrayGenotype[T]] {
+	n, mu := p.params.n, p.params.mu
+	generation := p.generation + 1
+	// Sort the agents from best to worst, and find the steps that the best mu took from the old mean
+	sorted := slices.Clone(p.agents)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Fitness > sorted[j].Fitness })
+	steps := make([]*mat.VecDense, mu)
+	for i := range steps {
+		steps[i] = mat.NewVecDense(n, nil)
+		for j := range n {
+			steps[i].SetVec(j, (float64(sorted[i].Genotype.At(j))-p.mean.AtVec(j))/p.sigma)
+		}
+	}
+	// Move the mean by the weighted average step
+	meanStep := mat.NewVecDense(n, nil)
+	for i, step := range steps {
+		meanStep.AddScaledVec(meanStep, p.params.weights[i], step)
+	}
+	mean := mat.NewVecDense(n, nil)
+	mean.AddScaledVec(p.mean, p.sigma, meanStep)
+	// Update the evolution path for sigma, which uses the step whitened by the inverse square root of the covariance
+	rotated := mat.NewVecDense(n, nil)
+	rotated.MulVec(p.basis.T(), meanStep)
+	for j := range n {
+		rotated.SetVec(j, rotated.AtVec(j)/p.scales[j])
+	}
+	whitened := mat.NewVecDense(n, nil)
+	whitened.MulVec(p.basis, rotated)
+	pathS := mat.NewVecDense(n, nil)
+	pathS.AddScaledVec(pathS, 1-p.params.cs, p.pathS)
+	pathS.AddScaledVec(pathS, math.Sqrt(p.params.cs*(2-p.params.cs)*p.params.muEff), whitened)
+	// Update the evolution path for the covariance, stalling it if the step size is growing too fast
+	normS := mat.Norm(pathS, 2)
+	hSig := normS/math.Sqrt(1-math.Pow(1-p.params.cs, 2*float64(generation)))/p.params.chiN < 1.4+2/float64(n+1)
+	pathC := mat.NewVecDense(n, nil)
+	pathC.AddScaledVec(pathC, 1-p.params.cc, p.pathC)
+	if hSig {
+		pathC.AddScaledVec(pathC, math.Sqrt(p.params.cc*(2-p.params.cc)*p.params.muEff), meanStep)
+	}
+	// Update the covariance with the rank-one update from the path and the rank-mu update from the steps
+	c1, cm := p.params.c1, p.params.cm
+	oldScale := 1 - c1 - cm
+	if !hSig {
+		oldScale += c1 * p.params.cc * (2 - p.params.cc)
+	}
+	cov := mat.NewSymDense(n, nil)
+	cov.ScaleSym(oldScale, p.cov)
+	cov.SymRankOne(cov, c1, pathC)
+	for i, step := range steps {
+		cov.SymRankOne(cov, cm*p.params.weights[i], step)
+	}
+	// Update the step size by comparing the length of the path to its expected length under random selection
+	sigma := p.sigma * math.Exp(p.params.cs/p.params.damps*(normS/p.params.chiN-1))
+	// Decompose the new covariance so that it can be sampled from
+	eigen := &mat.EigenSym{}
+	if !eigen.Factorize(cov, true) {
+		panic("failed to decompose covariance matrix")
+	}
+	basis := mat.NewDense(n, n, nil)
+	eigen.VectorsTo(basis)
+	scales := eigen.Values(nil)
+	for j := range scales {
+		// Rounding errors can make tiny eigenvalues negative
+		scales[j] = math.Sqrt(math.Max(scales[j], 1e-20))
+	}
+	next := &CMAESPopulation[T]{
+		rng:        p.rng,
+		generation: generation,
+		params:     p.params,
+		mean:       mean,
+		sigma:      sigma,
+		cov:        cov,
+		pathC:      pathC,
+		pathS:      pathS,
+		basis:      basis,
+		scales:     scales,
+	}
+	next.agents = next.sample()
+	return next
+}
+
+// All implements [Population].
+func (p *CMAESPopulation[T]) All() []*Agent[*ArrayGenotype[T]] {
+	return p.agents
+}
+
+// Mean returns the current mean of the search distribution, which is usually the best estimate of the optimum.
+func (p *CMAESPopulation[T]) Mean() []float64 {
+	return slices.Clone(p.mean.RawVector().Data)
+}
+
+// Sigma returns the current overall step size of the search distribution.
+func (p *CMAESPopulation[T]) Sigma() float64 {
+	return p.sigma
+}
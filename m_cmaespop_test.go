@@ -0,0 +1,36 @@
+package goevo
+
+import (
+	"math"
+	"testing"
+)
+
+// The ellipsoid function is badly conditioned, with each dimension a thousand times more important than the last
+func cmaesTestEllipsoid(x func(int) float64, n int) float64 {
+	total := 0.0
+	for i := range n {
+		total += math.Pow(1e6, float64(i)/float64(n-1)) * x(i) * x(i)
+	}
+	return -total
+}
+
+// Check that CMA-ES solves a badly conditioned problem with float64 and float32 genotypes
+func TestCMAESPopulation(t *testing.T) {
+	start := []float64{3, -2, 1, 4, -1, 2, -3, 1}
+	pop64 := NewCMAESPopulation[float64](NewRand(0), start, 1, 10)
+	best64, _ := NewRunner(func(g *ArrayGenotype[float64]) float64 {
+		return cmaesTestEllipsoid(g.At, g.Len())
+	}, 4, NewStopTargetFitness(-1e-8), NewStopMaxGenerations(2000)).Run(pop64)
+	if best64.Fitness < -1e-8 {
+		t.Fatalf("float64 CMA-ES did not converge, best fitness %v", best64.Fitness)
+	}
+
+	pop32 := NewCMAESPopulation[float32](NewRand(0), start, 1, 10)
+	best32, _ := NewRunner(func(g *ArrayGenotype[float32]) float64 {
+		return cmaesTestEllipsoid(func(i int) float64 { return float64(g.At(i)) }, g.Len())
+	}, 4, NewStopTargetFitness(-1e-4), NewStopMaxGenerations(2000)).Run(pop32)
+	if best32.Fitness < -1e-4 {
+		t.Fatalf("float32 CMA-ES did not converge, best fitness %v", best32.Fitness)
+	}
+	assertEq(t, len(pop32.Mean()), len(start), "mean length")
+}
@@ -1,6 +1,10 @@
 package goevo
 
-import "gonum.org/v1/gonum/mat"
+import (
+	"math/rand/v2"
+
+	"gonum.org/v1/gonum/mat"
+)
 
 // DenseGenotype is a type of genotype/phenotype that is a dense feed-forward neural network.
 type DenseGenotype struct {
@@ -162,18 +166,36 @@ func (m *denseMutationUniform) Mutate(g *DenseGenotype) {
 	}
 }
 
+// WithScaledStrength implements [Scalable] by scaling both the weight and bias generators, which must also be [Scalable].
+func (m *denseMutationUniform) WithScaledStrength(factor float64) any {
+	genWeights, okWeights := m.genWeights.(Scalable)
+	genBiases, okBiases := m.genBiases.(Scalable)
+	if !okWeights || !okBiases {
+		panic("dense mutation generators are not scalable")
+	}
+	c := *m
+	c.genWeights = genWeights.WithScaledStrength(factor).(Generator[float64])
+	c.genBiases = genBiases.WithScaledStrength(factor).(Generator[float64])
+	return &c
+}
+
 // denseCrossoverUniform is a type of crossover for dense genotypes.
 // For each weight and bias, it chooses randomly from one of its parents.
 // The number of parents is a parameter.
 type denseCrossoverUniform struct {
+	rng     *rand.Rand
 	parents int
 }
 
-func NewDenseCrossoverUniform(parents int) Crossover[*DenseGenotype] {
+func NewDenseCrossoverUniform(rng *rand.Rand, parents int) Crossover[*DenseGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
 	if parents <= 0 {
 		panic("must have at least one parent")
 	}
 	return &denseCrossoverUniform{
+		rng:     rng,
 		parents: parents,
 	}
 }
@@ -202,7 +224,7 @@ func (c *denseCrossoverUniform) Crossover(parents []*DenseGenotype) *DenseGenoty
 			}
 			pws[pi] = p.weights[wi]
 		}
-		randomChoiceMatrix(w, pws)
+		randomChoiceMatrix(c.rng, w, pws)
 	}
 	for bi, b := range g.biases {
 		br := b.Len()
@@ -214,7 +236,7 @@ func (c *denseCrossoverUniform) Crossover(parents []*DenseGenotype) *DenseGenoty
 			}
 			pbs[pi] = &mutVecWrapper{p.biases[bi]}
 		}
-		randomChoiceMatrix(&mutVecWrapper{b}, pbs)
+		randomChoiceMatrix(c.rng, &mutVecWrapper{b}, pbs)
 	}
 	return g
 }
@@ -5,17 +5,18 @@ import (
 )
 
 func setupDenseTestStuff(numIn, numOut int) Population[*DenseGenotype] {
-	selec := NewTournamentSelection[*DenseGenotype](3)
+	rng := NewRand(0)
+	selec := NewTournamentSelection[*DenseGenotype](rng, 3)
 
-	diffGen := NewGeneratorNormal(0.0, 0.1)
+	diffGen := NewGeneratorNormal(rng, 0.0, 0.1)
 	add := func(old, new float64) float64 { return old + new }
 	mut := NewDenseMutationUniform(diffGen, add, 0.1, diffGen, add, 0.1)
 
-	crs := NewDenseCrossoverUniform(2)
+	crs := NewDenseCrossoverUniform(rng, 2)
 
 	reprod := NewTwoPhaseReproduction(crs, mut)
 
-	gen := NewGeneratorNormal(0.0, 0.5)
+	gen := NewGeneratorNormal(rng, 0.0, 0.5)
 
 	var pop Population[*DenseGenotype] = NewSimplePopulation(func() *DenseGenotype {
 		return NewDenseGenotype([]int{numIn, 5, numOut}, Linear, Relu, Sigmoid, gen, gen)
@@ -0,0 +1,212 @@
+package goevo
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// DEStrategy is an enum representing the different ways that a [DifferentialEvolutionPopulation] creates mutant vectors.
+type DEStrategy int
+
+const (
+	// DERand1Bin creates each mutant from three random agents, as a + F(b - c).
+	DERand1Bin DEStrategy = iota
+	// DEBest1Bin creates each mutant from the best agent and two random agents, as best + F(b - c).
+	DEBest1Bin
+	// DECurrentToBest1Bin creates each mutant by moving the target towards the best agent, as target + F(best - target) + F(b - c).
+	DECurrentToBest1Bin
+)
+
+// String returns the name of the strategy in the usual DE/x/y/z notation.
+func (s DEStrategy) String() string {
+	switch s {
+	case DERand1Bin:
+		return "DE/rand/1/bin"
+	case DEBest1Bin:
+		return "DE/best/1/bin"
+	case DECurrentToBest1Bin:
+		return "DE/current-to-best/1/bin"
+	default:
+		panic(fmt.Sprintf("unknown differential evolution strategy %d", s))
+	}
+}
+
+// DifferentialEvolutionPopulation is a population that uses differential evolution to optimise a vector of floats.
+//
+// Each agent in the population is a target. Every generation, one trial is created for each target,
+// by adding scaled differences between other agents to a base vector (see [DEStrategy]),
+// then taking each gene from this mutant with probability CR (and at least one gene always), and otherwise from the target.
+// Once the trials have been evaluated, each trial replaces its own target if it is at least as fit.
+//
+// The targets and their trials are both returned by [DifferentialEvolutionPopulation.All], but only the trials need evaluating,
+// so the population is an [IncrementalPopulation].
+//
+// Optionally, F and CR can be self-adapted per agent as in jDE:
+// before creating each trial, the target's F is resampled from [0.1, 1) with probability 0.1, and its CR from [0, 1) with probability 0.1.
+// The new values are kept only if the trial replaces the target.
+type DifferentialEvolutionPopulation[T floatType] struct {
+	rng          *rand.Rand
+	strategy     DEStrategy
+	selfAdaptive bool
+	// targets are the current members of the population, and f and cr their control parameters.
+	targets  []*Agent[*ArrayGenotype[T]]
+	targetF  []float64
+	targetCR []float64
+	// trials are the candidates for each target, and f and cr the control parameters they were created with.
+	// They are nil in the first generation, when the targets themselves are unevaluated.
+	trials  []*Agent[*ArrayGenotype[T]]
+	trialF  []float64
+	trialCR []float64
+}
+
+// NewDifferentialEvolutionPopulation creates a new DifferentialEvolutionPopulation of n targets, each with a new genotype created by newGenotype.
+// All genotypes must have the same length.
+// The differential weight f is usually between 0.4 and 1, and the crossover rate cr between 0 and 1.
+// If selfAdaptive is true, f and cr are the starting values for every agent, which then adapt as in jDE.
+func NewDifferentialEvolutionPopulation[T floatType](
+	rng *rand.Rand,
+	newGenotype func() *ArrayGenotype[T],
+	n int,
+	strategy DEStrategy,
+	f float64,
+	cr float64,
+	selfAdaptive bool,
+) *DifferentialEvolutionPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if n < 4 {
+		panic("differential evolution requires at least 4 agents")
+	}
+	if f <= 0 {
+		panic("must have a differential weight greater than 0")
+	}
+	if cr < 0 || cr > 1 {
+		panic("crossover rate must be between 0 and 1")
+	}
+	if strategy < DERand1Bin || strategy > DECurrentToBest1Bin {
+		panic("unknown differential evolution strategy")
+	}
+	targets := make([]*Agent[*ArrayGenotype[T]], n)
+	targetF := make([]float64, n)
+	targetCR := make([]float64, n)
+	for i := range targets {
+		targets[i] = NewAgent(newGenotype())
+		if targets[i].Genotype.Len() != targets[0].Genotype.Len() {
+			panic("all genotypes must have the same length")
+		}
+		targetF[i], targetCR[i] = f, cr
+	}
+	return &DifferentialEvolutionPopulation[T]{
+		rng:          rng,
+		strategy:     strategy,
+		selfAdaptive: selfAdaptive,
+		targets:      targets,
+		targetF:      targetF,
+		targetCR:     targetCR,
+	}
+}
+
+// NextGeneration implements [Population].
+// Each evaluated trial replaces its target if it is at least as fit, then a new trial is created for every target.
+func (p *DifferentialEvolutionPopulation[T]) NextGeneration() Population[*ArrayGenotype[T]] {
+	next := &DifferentialEvolutionPopulation[T]{
+		rng:          p.rng,
+		strategy:     p.strategy,
+		selfAdaptive: p.selfAdaptive,
+		targets:      slices.Clone(p.targets),
+		targetF:      slices.Clone(p.targetF),
+		targetCR:     slices.Clone(p.targetCR),
+	}
+	for i, trial := range p.trials {
+		if trial.Fitness >= next.targets[i].Fitness {
+			next.targets[i], next.targetF[i], next.targetCR[i] = trial, p.trialF[i], p.trialCR[i]
+		}
+	}
+	best := 0
+	for i, t := range next.targets {
+		if t.Fitness > next.targets[best].Fitness {
+			best = i
+		}
+	}
+	n := len(next.targets)
+	next.trials = make([]*Agent[*ArrayGenotype[T]], n)
+	next.trialF = make([]float64, n)
+	next.trialCR = make([]float64, n)
+	for i, target := range next.targets {
+		f, cr := next.targetF[i], next.targetCR[i]
+		if p.selfAdaptive {
+			if p.rng.Float64() < 0.1 {
+				f = 0.1 + 0.9*p.rng.Float64()
+			}
+			if p.rng.Float64() < 0.1 {
+				cr = p.rng.Float64()
+			}
+		}
+		r := p.distinctIndices(n, i, 3)
+		a, b, c := next.targets[r[0]].Genotype, next.targets[r[1]].Genotype, next.targets[r[2]].Genotype
+		x, xBest := target.Genotype, next.targets[best].Genotype
+		length := x.Len()
+		forced := p.rng.IntN(length)
+		values := make([]T, length)
+		for j := range values {
+			if j != forced && p.rng.Float64() >= cr {
+				values[j] = x.At(j)
+				continue
+			}
+			var mutant float64
+			switch p.strategy {
+			case DERand1Bin:
+				mutant = float64(a.At(j)) + f*float64(b.At(j)-c.At(j))
+			case DEBest1Bin:
+				mutant = float64(xBest.At(j)) + f*float64(b.At(j)-c.At(j))
+			case DECurrentToBest1Bin:
+				mutant = float64(x.At(j)) + f*float64(xBest.At(j)-x.At(j)) + f*float64(b.At(j)-c.At(j))
+			}
+			values[j] = T(mutant)
+		}
+		next.trials[i] = NewAgent(&ArrayGenotype[T]{values: values})
+		next.trialF[i], next.trialCR[i] = f, cr
+	}
+	return next
+}
+
+// distinctIndices returns k distinct random indices below n, none of which are exclude.
+func (p *DifferentialEvolutionPopulation[T]) distinctIndices(n, exclude, k int) []int {
+	indices := make([]int, 0, k)
+	for len(indices) < k {
+		r := p.rng.IntN(n)
+		if r != exclude && !slices.Contains(indices, r) {
+			indices = append(indices, r)
+		}
+	}
+	return indices
+}
+
+// All implements [Population].
+// It returns the targets followed by their trials, if they have been created yet.
+func (p *DifferentialEvolutionPopulation[T]) All() []*Agent[*ArrayGenotype[T]] {
+	return append(slices.Clone(p.targets), p.trials...)
+}
+
+// Unevaluated implements [IncrementalPopulation].
+// It returns the trials, or the targets in the first generation.
+func (p *DifferentialEvolutionPopulation[T]) Unevaluated() []*Agent[*ArrayGenotype[T]] {
+	if p.trials == nil {
+		return p.targets
+	}
+	return p.trials
+}
+
+// Targets returns the current members of the population.
+// They have all been evaluated, except on a new population whose first generation has not been evaluated yet.
+func (p *DifferentialEvolutionPopulation[T]) Targets() []*Agent[*ArrayGenotype[T]] {
+	return slices.Clone(p.targets)
+}
+
+// ControlParameters returns the differential weight and crossover rate of each target.
+// These only change if the population is self-adaptive.
+func (p *DifferentialEvolutionPopulation[T]) ControlParameters() (f, cr []float64) {
+	return slices.Clone(p.targetF), slices.Clone(p.targetCR)
+}
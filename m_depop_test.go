@@ -0,0 +1,29 @@
+package goevo
+
+import (
+	"testing"
+)
+
+// Check that every strategy, with and without self-adaptation, solves a shifted sphere
+func TestDifferentialEvolutionPopulation(t *testing.T) {
+	fitness := func(g *ArrayGenotype[float64]) float64 {
+		total := 0.0
+		for i := range g.Len() {
+			total -= (g.At(i) - 2) * (g.At(i) - 2)
+		}
+		return total
+	}
+	for _, strategy := range []DEStrategy{DERand1Bin, DEBest1Bin, DECurrentToBest1Bin} {
+		for _, selfAdaptive := range []bool{false, true} {
+			rng := NewRand(0)
+			pop := NewDifferentialEvolutionPopulation(rng, func() *ArrayGenotype[float64] {
+				return NewArrayGenotype(6, NewGeneratorNormal(rng, 0.0, 3.0))
+			}, 40, strategy, 0.7, 0.9, selfAdaptive)
+			best, summary := NewRunner(fitness, 4, NewStopTargetFitness(-1e-6), NewStopMaxGenerations(2000)).Run(pop)
+			if best.Fitness < -1e-6 {
+				t.Fatalf("%v (self adaptive %v) did not converge, best fitness %v", strategy, selfAdaptive, best.Fitness)
+			}
+			assertEq(t, summary.Evaluations, 40*summary.Generations, "evaluations")
+		}
+	}
+}
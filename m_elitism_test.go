@@ -0,0 +1,68 @@
+package goevo
+
+import (
+	"math"
+	"testing"
+)
+
+// Check that the fittest agents are copied into the next generation, and only re-evaluated when asked
+func TestSimplePopulationElitism(t *testing.T) {
+	agents := make([]*Agent[int], 6)
+	for i := range agents {
+		agents[i] = &Agent[int]{Genotype: i, Fitness: float64(i)}
+	}
+	reprod := NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{})
+	pop := NewSimplePopulationFrom(agents, NewEliteSelection[int](), reprod).WithElitism(2, false)
+	assertEq(t, len(pop.Unevaluated()), 6, "first generation unevaluated")
+	next := NextGeneration(pop)
+	assertEq(t, next.All()[0].Genotype, 5, "best elite")
+	assertEq(t, next.All()[0].Fitness, 5.0, "elite keeps fitness")
+	assertEq(t, next.All()[1].Genotype, 4, "second elite")
+	assertEq(t, len(next.Unevaluated()), 4, "elites not re-evaluated")
+	assertEq(t, len(NextGeneration(pop.WithElitism(2, true)).Unevaluated()), 6, "elites re-evaluated")
+}
+
+// Check that the best fitness of each species never drops with elitism, even with a destructive mutation
+func TestSpeciatedPopulationElitism(t *testing.T) {
+	rng := NewRand(0)
+	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0.0, 1.0), 1)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), mut)
+	var pop Population[*ArrayGenotype[float64]] = NewSpeciatedPopulation(rng, NewCounter(), func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(3, NewGeneratorNormal(rng, 0.0, 1.0))
+	}, 3, 10, 0, 2, NewTournamentSelection[*ArrayGenotype[float64]](rng, 3), reprod).WithElitism(1, false)
+	fitness := func(g *ArrayGenotype[float64]) float64 { return -math.Abs(g.At(0)) }
+	lastBest := math.Inf(-1)
+	for range 20 {
+		EvaluateFitness(pop.(IncrementalPopulation[*ArrayGenotype[float64]]).Unevaluated(), fitness, 1)
+		best := math.Inf(-1)
+		for _, a := range pop.All() {
+			best = math.Max(best, a.Fitness)
+		}
+		if best < lastBest {
+			t.Fatalf("best fitness dropped from %v to %v", lastBest, best)
+		}
+		lastBest = best
+		pop = pop.NextGeneration()
+	}
+}
+
+// Check that elites never share a genotype, even when a species is copied twice after the worst species is removed
+func TestSpeciatedPopulationElitesNotShared(t *testing.T) {
+	rng := NewRand(0)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0.0, 1.0), 1))
+	var pop Population[*ArrayGenotype[float64]] = NewSpeciatedPopulation(rng, NewCounter(), func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(3, NewGeneratorNormal(rng, 0.0, 1.0))
+	}, 3, 5, 1, 0, NewTournamentSelection[*ArrayGenotype[float64]](rng, 3), reprod).WithElitism(2, true)
+	fitness := func(g *ArrayGenotype[float64]) float64 { return -math.Abs(g.At(0)) }
+	for range 10 {
+		EvaluateFitness(pop.All(), fitness, 1)
+		pop = pop.NextGeneration()
+		seen := make(map[*ArrayGenotype[float64]]bool)
+		for _, a := range pop.All() {
+			if seen[a.Genotype] {
+				t.Fatal("two agents share a genotype")
+			}
+			seen[a.Genotype] = true
+		}
+	}
+}
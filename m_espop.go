@@ -0,0 +1,219 @@
+package goevo
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+)
+
+// ESRecombination is an enum representing the ways that an [EvolutionStrategyPopulation] combines the rho parents of each child.
+type ESRecombination int
+
+const (
+	// ESIntermediate sets each value of the child to the mean of that value in its parents.
+	ESIntermediate ESRecombination = iota
+	// ESDiscrete sets each value of the child to that value from a randomly chosen parent.
+	ESDiscrete
+)
+
+// String returns the name of the recombination.
+func (r ESRecombination) String() string {
+	switch r {
+	case ESIntermediate:
+		return "intermediate"
+	case ESDiscrete:
+		return "discrete"
+	default:
+		panic(fmt.Sprintf("unknown evolution strategy recombination %d", r))
+	}
+}
+
+// EvolutionStrategyPopulation is a self-adaptive (mu/rho +, lambda) evolution strategy population, which optimises a vector of floats.
+//
+// Each agent carries its own mutation step sizes, either one for the whole genotype or one per gene.
+// Each generation, lambda children are created. Each child recombines rho random parents (both genes and step sizes),
+// then mutates its step sizes log-normally, and finally its genes with normal noise scaled by the new step sizes.
+// The best mu agents become the next parents, chosen from only the children (comma selection),
+// or from the children and the current parents (plus selection).
+//
+// The parents and children are both returned by [EvolutionStrategyPopulation.All], but only the children need evaluating,
+// so the population is an [IncrementalPopulation].
+type EvolutionStrategyPopulation[T floatType] struct {
+	rng           *rand.Rand
+	mu, rho       int
+	lambda        int
+	plus          bool
+	recombination ESRecombination
+	// parents are the current parents, and parentSigmas their step sizes. They are empty in the first generation.
+	parents      []*Agent[*ArrayGenotype[T]]
+	parentSigmas [][]float64
+	// children are the unevaluated agents, and childSigmas their step sizes.
+	children    []*Agent[*ArrayGenotype[T]]
+	childSigmas [][]float64
+}
+
+// NewEvolutionStrategyPopulation creates a new EvolutionStrategyPopulation.
+// The first generation has lambda agents, each with a new genotype created by newGenotype and every step size set to sigma.
+// All genotypes must have the same length. If perGeneSigma is true, each agent has one step size per gene, otherwise it has a single step size.
+// If plus is false (comma selection), lambda must be at least mu.
+func NewEvolutionStrategyPopulation[T floatType](
+	rng *rand.Rand,
+	newGenotype func() *ArrayGenotype[T],
+	sigma float64,
+	perGeneSigma bool,
+	mu, rho, lambda int,
+	plus bool,
+	recombination ESRecombination,
+) *EvolutionStrategyPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if sigma <= 0 {
+		panic("must have a step size greater than 0")
+	}
+	if mu <= 0 || lambda <= 0 {
+		panic("must have mu and lambda of at least 1")
+	}
+	if rho <= 0 || rho > mu {
+		panic("rho must be between 1 and mu")
+	}
+	if !plus && lambda < mu {
+		panic("comma selection requires lambda to be at least mu")
+	}
+	if recombination != ESIntermediate && recombination != ESDiscrete {
+		panic("unknown evolution strategy recombination")
+	}
+	children := make([]*Agent[*ArrayGenotype[T]], lambda)
+	childSigmas := make([][]float64, lambda)
+	for i := range children {
+		children[i] = NewAgent(newGenotype())
+		if children[i].Genotype.Len() != children[0].Genotype.Len() {
+			panic("all genotypes must have the same length")
+		}
+		numSigmas := 1
+		if perGeneSigma {
+			numSigmas = children[i].Genotype.Len()
+		}
+		childSigmas[i] = make([]float64, numSigmas)
+		for j := range childSigmas[i] {
+			childSigmas[i][j] = sigma
+		}
+	}
+	return &EvolutionStrategyPopulation[T]{
+		rng:           rng,
+		mu:            mu,
+		rho:           rho,
+		lambda:        lambda,
+		plus:          plus,
+		recombination: recombination,
+		children:      children,
+		childSigmas:   childSigmas,
+	}
+}
+
+// NextGeneration implements [Population].
+func (p *EvolutionStrategyPopulation[T]) NextGeneration() Population[*ArrayGenotype[T]] {
+	// Choose the new parents from the pool, from best to worst
+	pool := slices.Clone(p.children)
+	poolSigmas := slices.Clone(p.childSigmas)
+	if p.plus {
+		pool = append(pool, p.parents...)
+		poolSigmas = append(poolSigmas, p.parentSigmas...)
+	}
+	order := make([]int, len(pool))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return pool[order[i]].Fitness > pool[order[j]].Fitness })
+	numParents := min(p.mu, len(pool))
+	parents := make([]*Agent[*ArrayGenotype[T]], numParents)
+	parentSigmas := make([][]float64, numParents)
+	for i := range parents {
+		parents[i], parentSigmas[i] = pool[order[i]], poolSigmas[order[i]]
+	}
+	// Create the children
+	length := parents[0].Genotype.Len()
+	numSigmas := len(parentSigmas[0])
+	globalTau := 1 / math.Sqrt(2*float64(length))
+	localTau := 1 / math.Sqrt(2*math.Sqrt(float64(length)))
+	if numSigmas == 1 {
+		globalTau = 1 / math.Sqrt(float64(length))
+		localTau = 0
+	}
+	rho := min(p.rho, numParents)
+	children := make([]*Agent[*ArrayGenotype[T]], p.lambda)
+	childSigmas := make([][]float64, p.lambda)
+	for i := range children {
+		mates := p.rng.Perm(numParents)[:rho]
+		values := make([]float64, length)
+		sigmas := make([]float64, numSigmas)
+		p.recombine(values, func(m, j int) float64 { return float64(parents[m].Genotype.At(j)) }, mates)
+		p.recombine(sigmas, func(m, j int) float64 { return parentSigmas[m][j] }, mates)
+		global := globalTau * p.rng.NormFloat64()
+		for j := range sigmas {
+			sigmas[j] *= math.Exp(global + localTau*p.rng.NormFloat64())
+		}
+		genes := make([]T, length)
+		for j := range genes {
+			genes[j] = T(values[j] + sigmas[j%numSigmas]*p.rng.NormFloat64())
+		}
+		children[i] = NewAgent(&ArrayGenotype[T]{values: genes})
+		childSigmas[i] = sigmas
+	}
+	return &EvolutionStrategyPopulation[T]{
+		rng:           p.rng,
+		mu:            p.mu,
+		rho:           p.rho,
+		lambda:        p.lambda,
+		plus:          p.plus,
+		recombination: p.recombination,
+		parents:       parents,
+		parentSigmas:  parentSigmas,
+		children:      children,
+		childSigmas:   childSigmas,
+	}
+}
+
+// recombine fills into by combining value(m, j) over the mates m, using the population's recombination.
+func (p *EvolutionStrategyPopulation[T]) recombine(into []float64, value func(m, j int) float64, mates []int) {
+	for j := range into {
+		switch p.recombination {
+		case ESIntermediate:
+			total := 0.0
+			for _, m := range mates {
+				total += value(m, j)
+			}
+			into[j] = total / float64(len(mates))
+		case ESDiscrete:
+			into[j] = value(mates[p.rng.IntN(len(mates))], j)
+		}
+	}
+}
+
+// All implements [Population].
+// It returns the parents, followed by the children.
+func (p *EvolutionStrategyPopulation[T]) All() []*Agent[*ArrayGenotype[T]] {
+	return append(slices.Clone(p.parents), p.children...)
+}
+
+// Unevaluated implements [IncrementalPopulation].
+// It returns the children.
+func (p *EvolutionStrategyPopulation[T]) Unevaluated() []*Agent[*ArrayGenotype[T]] {
+	return p.children
+}
+
+// Parents returns the current parents, from best to worst.
+func (p *EvolutionStrategyPopulation[T]) Parents() []*Agent[*ArrayGenotype[T]] {
+	return slices.Clone(p.parents)
+}
+
+// ParentStepSizes returns the step sizes of each parent, in the same order as [EvolutionStrategyPopulation.Parents].
+func (p *EvolutionStrategyPopulation[T]) ParentStepSizes() [][]float64 {
+	sigmas := make([][]float64, len(p.parentSigmas))
+	for i := range sigmas {
+		sigmas[i] = slices.Clone(p.parentSigmas[i])
+	}
+	return sigmas
+}
@@ -0,0 +1,46 @@
+package goevo
+
+import (
+	"context"
+	"testing"
+)
+
+// Check that plus and comma strategies, with both recombinations and kinds of step size, solve a scaled sphere,
+// and that the step sizes shrink as the population converges
+func TestEvolutionStrategyPopulation(t *testing.T) {
+	fitness := func(g *ArrayGenotype[float64]) float64 {
+		total := 0.0
+		for i := range g.Len() {
+			total -= float64(i+1) * (g.At(i) - 1) * (g.At(i) - 1)
+		}
+		return total
+	}
+	for _, plus := range []bool{true, false} {
+		for _, recombination := range []ESRecombination{ESIntermediate, ESDiscrete} {
+			for _, perGene := range []bool{false, true} {
+				rng := NewRand(0)
+				pop := NewEvolutionStrategyPopulation(rng, func() *ArrayGenotype[float64] {
+					return NewArrayGenotype(5, NewGeneratorNormal(rng, 0.0, 1.0))
+				}, 1, perGene, 5, 2, 35, plus, recombination)
+				runner := NewRunner(fitness, 4, NewStopTargetFitness(-1e-6), NewStopMaxGenerations(2000))
+				best, final, summary, _ := runner.RunContext(context.Background(), pop)
+				if best.Fitness < -1e-6 {
+					t.Fatalf("plus %v, %v, per gene %v did not converge, best fitness %v", plus, recombination, perGene, best.Fitness)
+				}
+				assertEq(t, summary.Evaluations, 35*summary.Generations, "evaluations")
+				for _, sigmas := range NextGeneration(final.(*EvolutionStrategyPopulation[float64])).ParentStepSizes() {
+					if perGene {
+						assertEq(t, len(sigmas), 5, "num step sizes")
+					} else {
+						assertEq(t, len(sigmas), 1, "num step sizes")
+					}
+					for _, s := range sigmas {
+						if s > 0.1 {
+							t.Fatalf("step size did not shrink, %v", s)
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package goevo
+
+import (
+	"math"
+	"slices"
+	"sort"
+)
+
+// fitnessSharing is a [FitnessAdjustment] that divides each score by the number of similar agents, within a niche radius.
+type fitnessSharing[T any] struct {
+	distance func(a, b T) float64
+	radius   float64
+	alpha    float64
+}
+
+// NewFitnessSharing creates a new [FitnessAdjustment] for fitness sharing, which stops many agents crowding into the same niche.
+// Each score is divided by its niche count, which is the sum of 1 - (d/radius)^alpha over every agent (including itself) at a distance d less than radius.
+// The distance function compares two genotypes, and alpha is usually 1.
+// Sharing only makes sense for positive scores, so if any score is negative, all scores are first shifted so that the smallest is 0.
+func NewFitnessSharing[T any](distance func(a, b T) float64, radius, alpha float64) FitnessAdjustment[T] {
+	if distance == nil {
+		panic("cannot have nil distance function")
+	}
+	if radius <= 0 {
+		panic("must have a radius greater than 0")
+	}
+	if alpha <= 0 {
+		panic("must have an alpha greater than 0")
+	}
+	return &fitnessSharing[T]{
+		distance: distance,
+		radius:   radius,
+		alpha:    alpha,
+	}
+}
+
+// Adjust implements [FitnessAdjustment].
+func (f *fitnessSharing[T]) Adjust(agents []*Agent[T], scores []float64) []float64 {
+	shift := math.Min(0, slices.Min(scores))
+	adjusted := make([]float64, len(scores))
+	for i, a := range agents {
+		nicheCount := 0.0
+		for _, b := range agents {
+			if d := f.distance(a.Genotype, b.Genotype); d < f.radius {
+				nicheCount += 1 - math.Pow(d/f.radius, f.alpha)
+			}
+		}
+		adjusted[i] = (scores[i] - shift) / nicheCount
+	}
+	return adjusted
+}
+
+// linearScaling is a [FitnessAdjustment] that scales scores linearly, so that the best has a fixed multiple of the mean.
+type linearScaling[T any] struct {
+	multiple float64
+}
+
+// NewLinearScaling creates a new [FitnessAdjustment] for linear fitness scaling, which controls selection pressure.
+// Scores are scaled linearly so that the mean is unchanged and the best becomes multiple times the mean (usually between 1.2 and 2).
+// If that would make any score negative, the scaling is reduced so that the worst score becomes 0 instead.
+// Scaling only makes sense for positive scores, so if any score is negative, all scores are first shifted so that the smallest is 0.
+func NewLinearScaling[T any](multiple float64) FitnessAdjustment[T] {
+	if multiple < 1 {
+		panic("multiple must be at least 1")
+	}
+	return &linearScaling[T]{
+		multiple: multiple,
+	}
+}
+
+// Adjust implements [FitnessAdjustment].
+func (f *linearScaling[T]) Adjust(agents []*Agent[T], scores []float64) []float64 {
+	shift := math.Min(0, slices.Min(scores))
+	adjusted := make([]float64, len(scores))
+	for i, s := range scores {
+		adjusted[i] = s - shift
+	}
+	mean, _ := meanStd(adjusted)
+	lo, hi := slices.Min(adjusted), slices.Max(adjusted)
+	if hi == mean {
+		return adjusted
+	}
+	a := (f.multiple - 1) * mean / (hi - mean)
+	b := mean * (1 - a)
+	if a*lo+b < 0 {
+		a = mean / (mean - lo)
+		b = -lo * a
+	}
+	for i := range adjusted {
+		adjusted[i] = a*adjusted[i] + b
+	}
+	return adjusted
+}
+
+// sigmaTruncation is a [FitnessAdjustment] that subtracts a multiple of the standard deviation below the mean from each score.
+type sigmaTruncation[T any] struct {
+	c float64
+}
+
+// NewSigmaTruncation creates a new [FitnessAdjustment] for sigma truncation.
+// Each score becomes max(0, score - (mean - c*std)), so scores more than c standard deviations below the mean are truncated to 0.
+// The multiple c is usually between 1 and 3.
+func NewSigmaTruncation[T any](c float64) FitnessAdjustment[T] {
+	if c < 0 {
+		panic("cannot have negative c")
+	}
+	return &sigmaTruncation[T]{
+		c: c,
+	}
+}
+
+// Adjust implements [FitnessAdjustment].
+func (f *sigmaTruncation[T]) Adjust(agents []*Agent[T], scores []float64) []float64 {
+	mean, std := meanStd(scores)
+	adjusted := make([]float64, len(scores))
+	for i, s := range scores {
+		adjusted[i] = math.Max(0, s-(mean-f.c*std))
+	}
+	return adjusted
+}
+
+// rankFitness is a [FitnessAdjustment] that replaces each score with its rank.
+type rankFitness[T any] struct{}
+
+// NewRankFitness creates a new [FitnessAdjustment] that replaces each score with its rank, from 1 for the worst to n for the best.
+// Equal scores share the mean of their ranks. This keeps selection pressure the same no matter how the scores are spread out.
+func NewRankFitness[T any]() FitnessAdjustment[T] {
+	return &rankFitness[T]{}
+}
+
+// Adjust implements [FitnessAdjustment].
+func (f *rankFitness[T]) Adjust(agents []*Agent[T], scores []float64) []float64 {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] < scores[order[j]] })
+	adjusted := make([]float64, len(scores))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && scores[order[end]] == scores[order[start]] {
+			end++
+		}
+		// Ranks start..end-1 (counting from 0) are tied, so they all get the mean rank counting from 1
+		rank := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			adjusted[i] = rank
+		}
+		start = end
+	}
+	return adjusted
+}
+
+// speciesSharing is a [FitnessAdjustment] that divides each score by the size of its species.
+type speciesSharing[T any] struct {
+	speciesOf func(*Agent[T]) int
+}
+
+// NewSpeciesSharing creates a new [FitnessAdjustment] that divides each score by the number of agents in its species, as in NEAT.
+// The speciesOf function returns the species ID of an agent, for example by looking it up in [NeatPopulation.AllSpecies].
+// Only the agents given to the selection at once are counted, so this has no effect when a population selects from one species at a time.
+func NewSpeciesSharing[T any](speciesOf func(*Agent[T]) int) FitnessAdjustment[T] {
+	if speciesOf == nil {
+		panic("cannot have nil species function")
+	}
+	return &speciesSharing[T]{
+		speciesOf: speciesOf,
+	}
+}
+
+// Adjust implements [FitnessAdjustment].
+func (f *speciesSharing[T]) Adjust(agents []*Agent[T], scores []float64) []float64 {
+	species := make([]int, len(agents))
+	sizes := make(map[int]int)
+	for i, a := range agents {
+		species[i] = f.speciesOf(a)
+		sizes[species[i]]++
+	}
+	adjusted := make([]float64, len(scores))
+	for i, s := range scores {
+		adjusted[i] = s / float64(sizes[species[i]])
+	}
+	return adjusted
+}
+
+// meanStd returns the mean and population standard deviation of xs.
+func meanStd(xs []float64) (float64, float64) {
+	mean := 0.0
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	variance := 0.0
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(xs)))
+}
@@ -0,0 +1,87 @@
+package goevo
+
+import (
+	"math"
+	"testing"
+)
+
+func fitnessAdjustmentTestAgents(fitnesses ...float64) []*Agent[float64] {
+	agents := make([]*Agent[float64], len(fitnesses))
+	for i, f := range fitnesses {
+		agents[i] = &Agent[float64]{Genotype: f, Fitness: f}
+	}
+	return agents
+}
+
+func adjustRaw(adj FitnessAdjustment[float64], agents []*Agent[float64]) []float64 {
+	scores := make([]float64, len(agents))
+	for i, a := range agents {
+		scores[i] = a.Fitness
+	}
+	return adj.Adjust(agents, scores)
+}
+
+// Check each fitness adjustment against hand calculated scores
+func TestFitnessAdjustments(t *testing.T) {
+	agents := fitnessAdjustmentTestAgents(1, 2, 3, 6)
+
+	// Each genotype is the same as its fitness, so 1, 2, and 3 are within the radius of their neighbours, but 6 is alone
+	sharing := NewFitnessSharing(func(a, b float64) float64 { return math.Abs(a - b) }, 2, 1)
+	shared := adjustRaw(sharing, agents)
+	assertScoresNear(t, shared, []float64{1 / 1.5, 2 / 2.0, 3 / 1.5, 6}, "fitness sharing")
+
+	// The mean is 3, so the best becomes 4.5 with a multiple of 1.5
+	scaled := adjustRaw(NewLinearScaling[float64](1.5), agents)
+	assertScoresNear(t, scaled, []float64{2, 2.5, 3, 4.5}, "linear scaling")
+	// A multiple of 3 would make the worst score negative, so it is clipped to 0 instead
+	scaled = adjustRaw(NewLinearScaling[float64](3), agents)
+	assertScoresNear(t, scaled, []float64{0, 1.5, 3, 7.5}, "linear scaling clipped")
+
+	truncated := adjustRaw(NewSigmaTruncation[float64](0), agents)
+	assertScoresNear(t, truncated, []float64{0, 0, 0, 3}, "sigma truncation")
+
+	ranked := adjustRaw(NewRankFitness[float64](), fitnessAdjustmentTestAgents(5, -1, 5, 0))
+	assertScoresNear(t, ranked, []float64{3.5, 1, 3.5, 2}, "rank fitness")
+
+	species := NewSpeciesSharing(func(a *Agent[float64]) int { return int(a.Genotype) / 3 })
+	assertScoresNear(t, adjustRaw(species, agents), []float64{0.5, 1, 3, 6}, "species sharing")
+}
+
+// Check that an adjusted selection selects by the adjusted score without changing the raw fitness
+func TestAdjustedSelection(t *testing.T) {
+	agents := fitnessAdjustmentTestAgents(1, 10, 2)
+	// Negating the scores makes the elite selection pick the worst agent
+	negate := fitnessAdjustmentFunc(func(scores []float64) []float64 {
+		negated := make([]float64, len(scores))
+		for i, s := range scores {
+			negated[i] = -s
+		}
+		return negated
+	})
+	selec := NewAdjustedSelection(NewEliteSelection[float64](), negate)
+	selec.SetAgents(agents)
+	assertEq(t, selec.Select(), agents[0], "selected by adjusted score")
+	assertEq(t, agents[1].Fitness, 10.0, "raw fitness kept")
+
+	// Adjustments are applied in order
+	selec = NewAdjustedSelection(NewEliteSelection[float64](), negate, NewRankFitness[float64](), negate)
+	selec.SetAgents(agents)
+	assertEq(t, selec.Select(), agents[1], "chained adjustments")
+}
+
+func assertScoresNear(t *testing.T, got, want []float64, name string) {
+	t.Helper()
+	ok := len(got) == len(want)
+	for i := 0; ok && i < len(got); i++ {
+		ok = math.Abs(got[i]-want[i]) < 1e-9
+	}
+	if !ok {
+		t.Fatalf("error in check '%s': got %v, want %v", name, got, want)
+	}
+}
+
+type fitnessAdjustmentFunc func([]float64) []float64
+
+func (f fitnessAdjustmentFunc) Adjust(_ []*Agent[float64], scores []float64) []float64 {
+	return f(scores)
+}
@@ -1,36 +1,138 @@
 package goevo
 
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// HillClimberPopulation has two agents: a, the current solution, and b, a mutated candidate of it.
+// Each generation, either a or b is kept, and becomes both the new a and the parent of the new b.
+// By default the selection chooses which to keep, but simulated annealing can be used instead with [HillClimberPopulation.WithAnnealing].
+// The mutation strength can also be adapted with the 1/5th success rule using [HillClimberPopulation.WithSuccessRule].
 type HillClimberPopulation[T any] struct {
 	a            *Agent[T]
 	b            *Agent[T]
 	selection    Selection[T]
 	reproduction Reproduction[T]
+	// rng and schedule are used for simulated annealing, which is disabled if schedule is nil.
+	rng      *rand.Rand
+	schedule TemperatureSchedule
+	// successWindow and successFactor are the parameters of the 1/5th success rule, which is disabled if successWindow is 0.
+	successWindow int
+	successFactor float64
+	// successes and steps count the successful candidates in the current window of the 1/5th success rule.
+	successes int
+	steps     int
+	// accepted and rejected count the candidates that were kept and discarded.
+	accepted int
+	rejected int
 }
 
 func NewHillClimberPopulation[T any](initialA, initialB T, selection Selection[T], reproduction Reproduction[T]) *HillClimberPopulation[T] {
+	return NewHillClimberPopulationFrom(NewAgent(initialA), NewAgent(initialB), selection, reproduction)
+}
+
+// NewHillClimberPopulationFrom creates a new HillClimberPopulation from two existing agents.
+// The agents keep their fitness, so this can be used to continue a population from a [Checkpoint].
+func NewHillClimberPopulationFrom[T any](a, b *Agent[T], selection Selection[T], reproduction Reproduction[T]) *HillClimberPopulation[T] {
 	if selection == nil {
 		panic("cannot have nil selection")
 	}
 	if reproduction == nil {
 		panic("cannot have nil reproduction")
 	}
+	if a == nil || b == nil {
+		panic("cannot have nil agent")
+	}
 	return &HillClimberPopulation[T]{
-		a:            NewAgent(initialA),
-		b:            NewAgent(initialB),
+		a:            a,
+		b:            b,
 		selection:    selection,
 		reproduction: reproduction,
 	}
 }
 
+// WithAnnealing returns a copy of the population that uses simulated annealing to decide whether to keep the candidate b, instead of the selection.
+// The candidate is always kept if it is at least as fit as a, and otherwise it is kept with probability exp(-loss / temperature),
+// where loss is how much less fit it is and the temperature comes from the schedule.
+// The schedule is stepped in place each generation, so it should not be shared with other populations.
+func (p *HillClimberPopulation[T]) WithAnnealing(rng *rand.Rand, schedule TemperatureSchedule) *HillClimberPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if schedule == nil {
+		panic("cannot have nil schedule")
+	}
+	np := *p
+	np.rng = rng
+	np.schedule = schedule
+	return &np
+}
+
+// WithSuccessRule returns a copy of the population that adapts the mutation strength using Rechenberg's 1/5th success rule.
+// Every window generations, if more than 1/5 of the candidates were fitter than a, the strength is divided by factor, and if fewer were, it is multiplied by factor.
+// The factor must be between 0 and 1, and is usually about 0.82.
+// The reproduction must be [Scalable], as must anything it is made of, such as the mutation of a two phase reproduction.
+// The returned population adapts its own copy of the reproduction, so the original reproduction is never modified.
+func (p *HillClimberPopulation[T]) WithSuccessRule(window int, factor float64) *HillClimberPopulation[T] {
+	if window <= 0 {
+		panic("must have a window of at least 1")
+	}
+	if factor <= 0 || factor >= 1 {
+		panic("factor must be between 0 and 1")
+	}
+	reproduction, ok := p.reproduction.(Scalable)
+	if !ok {
+		panic("reproduction must be scalable to use the success rule")
+	}
+	np := *p
+	// This also checks now that everything inside the reproduction is scalable, rather than part way through a run
+	np.reproduction = reproduction.WithScaledStrength(1).(Reproduction[T])
+	np.successWindow = window
+	np.successFactor = factor
+	np.successes, np.steps = 0, 0
+	return &np
+}
+
 func (p *HillClimberPopulation[T]) NextGeneration() Population[T] {
 	if p.reproduction.NumParents() != 1 {
 		panic("Hillclimber only supports reproduction with 1 parent")
 	}
-	p.selection.SetAgents(p.All())
-	parent := p.selection.Select()
-	a := NewAgent(parent.Genotype)
-	b := NewAgent(p.reproduction.Reproduce([]T{parent.Genotype}))
-	return &HillClimberPopulation[T]{a: a, b: b, selection: p.selection, reproduction: p.reproduction}
+	next := *p
+	var parent *Agent[T]
+	if p.schedule != nil {
+		parent = p.a
+		if p.b.Fitness >= p.a.Fitness || p.rng.Float64() < math.Exp((p.b.Fitness-p.a.Fitness)/p.schedule.Temperature()) {
+			parent = p.b
+		}
+		p.schedule.Step(parent == p.b)
+	} else {
+		p.selection.SetAgents(p.All())
+		parent = p.selection.Select()
+	}
+	if parent == p.b {
+		next.accepted++
+	} else {
+		next.rejected++
+	}
+	if p.successWindow > 0 {
+		next.steps++
+		if p.b.Fitness > p.a.Fitness {
+			next.successes++
+		}
+		if next.steps == p.successWindow {
+			rate := float64(next.successes) / float64(next.steps)
+			if rate > 0.2 {
+				next.reproduction = p.reproduction.(Scalable).WithScaledStrength(1 / p.successFactor).(Reproduction[T])
+			} else if rate < 0.2 {
+				next.reproduction = p.reproduction.(Scalable).WithScaledStrength(p.successFactor).(Reproduction[T])
+			}
+			next.successes, next.steps = 0, 0
+		}
+	}
+	next.a = NewAgent(parent.Genotype)
+	next.b = NewAgent(ReproduceAgents(next.reproduction, []*Agent[T]{parent}))
+	return &next
 }
 
 func (p *HillClimberPopulation[T]) All() []*Agent[T] {
@@ -40,3 +142,13 @@ func (p *HillClimberPopulation[T]) All() []*Agent[T] {
 func (p *HillClimberPopulation[T]) Both() (*Agent[T], *Agent[T]) {
 	return p.a, p.b
 }
+
+// Accepted returns the number of generations in which the candidate b was kept.
+func (p *HillClimberPopulation[T]) Accepted() int {
+	return p.accepted
+}
+
+// Rejected returns the number of generations in which the candidate b was discarded.
+func (p *HillClimberPopulation[T]) Rejected() int {
+	return p.rejected
+}
@@ -0,0 +1,74 @@
+package goevo
+
+import (
+	"context"
+	"testing"
+)
+
+// Check that each schedule cools as expected
+func TestTemperatureSchedules(t *testing.T) {
+	exp := NewExponentialSchedule(10, 0.5)
+	exp.Step(false)
+	exp.Step(true)
+	assertEq(t, exp.Temperature(), 2.5, "exponential")
+
+	lin := NewLinearSchedule(10, 4)
+	for range 6 {
+		lin.Step(false)
+	}
+	assertEq(t, lin.Temperature(), 0.0, "linear")
+
+	adaptive := NewAdaptiveSchedule(10, 0.5, 0.5, 2)
+	adaptive.Step(true)
+	adaptive.Step(true)
+	assertEq(t, adaptive.Temperature(), 5.0, "adaptive accepting too many")
+	adaptive.Step(false)
+	adaptive.Step(false)
+	assertEq(t, adaptive.Temperature(), 10.0, "adaptive accepting too few")
+}
+
+// Check that annealing accepts worse candidates while hot but not once cold, and counts them
+func TestHillClimberAnnealing(t *testing.T) {
+	rng := NewRand(0)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0.0, 0.1), 1))
+	g := NewArrayGenotype(3, NewGeneratorNormal(rng, 0.0, 1.0))
+	for _, temperature := range []float64{1e9, 0} {
+		a, b := &Agent[*ArrayGenotype[float64]]{Genotype: g, Fitness: 1}, &Agent[*ArrayGenotype[float64]]{Genotype: Clone(g), Fitness: 0}
+		pop := NewHillClimberPopulationFrom(a, b, NewEliteSelection[*ArrayGenotype[float64]](), reprod).
+			WithAnnealing(rng, NewExponentialSchedule(temperature, 0.99))
+		next := NextGeneration(pop)
+		if temperature > 0 {
+			assertEq(t, next.Accepted(), 1, "hot accepted")
+		} else {
+			assertEq(t, next.Rejected(), 1, "cold rejected")
+		}
+	}
+}
+
+// Check that the 1/5th success rule shrinks the mutation strength as a hill climber converges on a sphere
+func TestHillClimberSuccessRule(t *testing.T) {
+	rng := NewRand(0)
+	gen := NewGeneratorNormal(rng, 0.0, 1.0)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), NewArrayMutationGeneratorAdd(gen, 1))
+	g := NewArrayGenotype(5, NewGeneratorNormal(rng, 0.0, 3.0))
+	pop := NewHillClimberPopulation(g, Clone(g), NewEliteSelection[*ArrayGenotype[float64]](), reprod).WithSuccessRule(10, 0.82)
+	fitness := func(g *ArrayGenotype[float64]) float64 {
+		total := 0.0
+		for i := range g.Len() {
+			total -= g.At(i) * g.At(i)
+		}
+		return total
+	}
+	runner := NewRunner(fitness, 1, NewStopTargetFitness(-1e-8), NewStopMaxGenerations(5000))
+	best, final, summary, _ := runner.RunContext(context.Background(), pop)
+	if best.Fitness < -1e-8 {
+		t.Fatalf("hill climber did not converge, best fitness %v", best.Fitness)
+	}
+	hc := final.(*HillClimberPopulation[*ArrayGenotype[float64]])
+	std := hc.reproduction.(*twoPhaseReproduction[*ArrayGenotype[float64]]).mutate.(*arrayMutationGenerator[float64]).gen.(*generatorNormal[float64]).std
+	if std > 0.01 {
+		t.Fatalf("mutation strength did not shrink, std %v", std)
+	}
+	assertEq(t, gen.(*generatorNormal[float64]).std, 1.0, "original generator unchanged")
+	assertEq(t, hc.Accepted()+hc.Rejected(), summary.Generations-1, "accepted and rejected counts")
+}
@@ -0,0 +1,203 @@
+package goevo
+
+import (
+	"math"
+	"slices"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Substrate describes the positions of the neurons in a layered network, so that a CPPN can be decoded into that network, as in HyperNEAT.
+// The CPPN is a [NeatGenotype] that is queried with the positions of the two ends of every possible synapse, to find the weight of that synapse.
+// This means a small CPPN can describe a very large network, with weights that follow regular patterns in the geometry of the substrate.
+//
+// The CPPN must have [Substrate.NumCPPNInputs] inputs, which are the coordinates of the source neuron, then the target neuron, then a constant 1.
+// It must have [Substrate.NumCPPNOutputs] outputs, which are the weight, the bias, and (if enabled) the expression output.
+// The outputs should be in the range [-1, 1], for example by creating the CPPN with a [Tanh] output activation.
+type Substrate struct {
+	// layers are the coordinates of each neuron in each layer, from the inputs to the outputs.
+	layers [][][]float64
+	// dims is the number of coordinates of every neuron.
+	dims             int
+	hiddenActivation Activation
+	outputActivation Activation
+	// weightThreshold is the magnitude that a CPPN output must be above for its synapse to be expressed.
+	weightThreshold float64
+	// maxWeight is the magnitude of the weight of a synapse when the CPPN output is 1 or -1.
+	maxWeight float64
+	// expressionOutput is whether the CPPN has an extra output which decides whether each synapse is expressed, instead of the threshold.
+	expressionOutput bool
+}
+
+// NewSubstrate creates a new [Substrate], where the neurons in each layer are fully connected to the neurons in the next layer.
+// Each layer is a list of neuron positions, and every position must have the same number of coordinates (usually 2 or 3).
+// The first layer is the inputs and the last layer is the outputs, so there must be at least two layers.
+//
+// By default, synapses are only expressed if the magnitude of the CPPN weight output is above 0.2, and the maximum weight is 3.
+// This can be changed with [Substrate.WithExpression].
+func NewSubstrate(layers [][][]float64, hiddenActivation, outputActivation Activation) *Substrate {
+	if len(layers) < 2 {
+		panic("cannot have fewer than two layers")
+	}
+	dims := -1
+	for _, layer := range layers {
+		if len(layer) == 0 {
+			panic("cannot have a layer with no neurons")
+		}
+		for _, pos := range layer {
+			if dims == -1 {
+				dims = len(pos)
+			}
+			if len(pos) == 0 || len(pos) != dims {
+				panic("all neuron positions must have the same number of coordinates")
+			}
+		}
+	}
+	ls := make([][][]float64, len(layers))
+	for i, layer := range layers {
+		ls[i] = make([][]float64, len(layer))
+		for j, pos := range layer {
+			ls[i][j] = slices.Clone(pos)
+		}
+	}
+	return &Substrate{
+		layers:           ls,
+		dims:             dims,
+		hiddenActivation: hiddenActivation,
+		outputActivation: outputActivation,
+		weightThreshold:  0.2,
+		maxWeight:        3,
+	}
+}
+
+// WithExpression returns a copy of the substrate with different settings for how the CPPN outputs are expressed as synapses.
+//
+// If expressionOutput is false, a synapse is only expressed if the magnitude of the CPPN weight output is above weightThreshold,
+// and the magnitudes above the threshold are scaled to the range (0, maxWeight].
+// If expressionOutput is true, the CPPN has an extra output (as in HyperNEAT-LEO), and a synapse is only expressed if that output is above 0.
+// The threshold is then not used, and the weight output is scaled from [-1, 1] to [-maxWeight, maxWeight].
+// Biases are scaled in the same way as weights, but are always expressed.
+func (s *Substrate) WithExpression(weightThreshold, maxWeight float64, expressionOutput bool) *Substrate {
+	if weightThreshold < 0 || weightThreshold >= 1 {
+		panic("weight threshold must be at least 0 and less than 1")
+	}
+	if maxWeight <= 0 {
+		panic("max weight must be greater than 0")
+	}
+	ns := *s
+	ns.weightThreshold = weightThreshold
+	ns.maxWeight = maxWeight
+	ns.expressionOutput = expressionOutput
+	return &ns
+}
+
+// SubstrateGrid returns the positions of a width by height grid of neurons, spread evenly over [-1, 1] in x and y, at the given z.
+// This is useful for 3D substrates that take an image as input, with one layer of the substrate at each z.
+func SubstrateGrid(width, height int, z float64) [][]float64 {
+	if width <= 0 || height <= 0 {
+		panic("grid must be at least 1x1")
+	}
+	spread := func(i, n int) float64 {
+		if n == 1 {
+			return 0
+		}
+		return -1 + 2*float64(i)/float64(n-1)
+	}
+	positions := make([][]float64, 0, width*height)
+	for y := range height {
+		for x := range width {
+			positions = append(positions, []float64{spread(x, width), spread(y, height), z})
+		}
+	}
+	return positions
+}
+
+// NumCPPNInputs returns the number of inputs a CPPN needs to be decoded by this substrate.
+func (s *Substrate) NumCPPNInputs() int {
+	return 2*s.dims + 1
+}
+
+// NumCPPNOutputs returns the number of outputs a CPPN needs to be decoded by this substrate.
+func (s *Substrate) NumCPPNOutputs() int {
+	if s.expressionOutput {
+		return 3
+	}
+	return 2
+}
+
+// Shape returns the number of neurons in each layer of the substrate.
+func (s *Substrate) Shape() []int {
+	shape := make([]int, len(s.layers))
+	for i, layer := range s.layers {
+		shape[i] = len(layer)
+	}
+	return shape
+}
+
+// Decode queries the CPPN for the weight of every synapse and the bias of every non-input neuron in the substrate, and returns the resulting network.
+// The network is a [DenseGenotype] with a linear input activation, so it can be used as a [Forwarder] directly, or be refined further with dense mutations.
+// Synapses that are not expressed have a weight of 0.
+// Biases are found by querying the CPPN with the source position at the origin.
+func (s *Substrate) Decode(cppn *NeatGenotype) *DenseGenotype {
+	if cppn.NumInputNeurons() != s.NumCPPNInputs() {
+		panic("cppn has the wrong number of inputs for the substrate")
+	}
+	if cppn.NumOutputNeurons() != s.NumCPPNOutputs() {
+		panic("cppn has the wrong number of outputs for the substrate")
+	}
+	phenotype := cppn.Build().(*NeatPhenotype)
+	input := make([]float64, s.NumCPPNInputs())
+	query := func(from, to []float64) []float64 {
+		copy(input, from)
+		copy(input[s.dims:], to)
+		input[2*s.dims] = 1
+		// Reset so that any recurrent synapses in the CPPN do not make the result depend on the previous query
+		phenotype.Reset()
+		return phenotype.Forward(input)
+	}
+	origin := make([]float64, s.dims)
+
+	g := &DenseGenotype{
+		weights:          make([]*mat.Dense, len(s.layers)-1),
+		biases:           make([]*mat.VecDense, len(s.layers)),
+		buffers:          make([]*mat.VecDense, len(s.layers)),
+		inputActivation:  Linear,
+		hiddenActivation: s.hiddenActivation,
+		outputActivation: s.outputActivation,
+	}
+	g.biases[0] = mat.NewVecDense(len(s.layers[0]), nil)
+	g.buffers[0] = mat.NewVecDense(len(s.layers[0]), nil)
+	for l := 1; l < len(s.layers); l++ {
+		from, to := s.layers[l-1], s.layers[l]
+		weights := mat.NewDense(len(to), len(from), nil)
+		biases := mat.NewVecDense(len(to), nil)
+		for j, tp := range to {
+			for i, fp := range from {
+				out := query(fp, tp)
+				if s.expressionOutput && out[2] <= 0 {
+					continue
+				}
+				weights.Set(j, i, s.scale(out[0], !s.expressionOutput))
+			}
+			biases.SetVec(j, s.scale(query(origin, tp)[1], false))
+		}
+		g.weights[l-1] = weights
+		g.biases[l] = biases
+		g.buffers[l] = mat.NewVecDense(len(to), nil)
+	}
+	return g
+}
+
+// scale converts a CPPN output into a weight, clamping it to [-1, 1] first.
+// If threshold is true, outputs with a magnitude at or below the weight threshold become 0, and the rest are scaled to start from 0.
+func (s *Substrate) scale(x float64, threshold bool) float64 {
+	x = clamp(x, -1, 1)
+	if !threshold {
+		return x * s.maxWeight
+	}
+	if math.Abs(x) <= s.weightThreshold {
+		return 0
+	}
+	magnitude := (math.Abs(x) - s.weightThreshold) / (1 - s.weightThreshold)
+	return math.Copysign(magnitude*s.maxWeight, x)
+}
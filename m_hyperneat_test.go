@@ -0,0 +1,82 @@
+package goevo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// cppnFromJSON loads a hand-written CPPN with linear outputs, where the input neurons have IDs 0 to numIn-1 and the outputs the IDs after.
+func cppnFromJSON(t *testing.T, numIn, numOut int, synapses string) *NeatGenotype {
+	neurons := make([]string, numIn+numOut)
+	for i := range neurons {
+		neurons[i] = fmt.Sprintf(`{"id":%d,"activation":"linear"}`, i)
+	}
+	js := fmt.Sprintf(`{"num_in":%d,"num_out":%d,"neurons":[%s],"synapses":[%s],"max_synapse_val":3}`,
+		numIn, numOut, strings.Join(neurons, ","), synapses)
+	g := &NeatGenotype{}
+	if err := json.Unmarshal([]byte(js), g); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// Check that the weights and biases of the decoded network follow the CPPN, with thresholding and an expression output
+func TestSubstrateDecode(t *testing.T) {
+	layers := [][][]float64{
+		{{-1, 0}, {0.1, 0}, {1, 0}},
+		{{0, 1}},
+	}
+	substrate := NewSubstrate(layers, Linear, Linear)
+	assertEq(t, substrate.NumCPPNInputs(), 5, "number of cppn inputs")
+	assertEq(t, substrate.NumCPPNOutputs(), 2, "number of cppn outputs")
+	// The weight is the x position of the source neuron, and the bias is half of the constant input
+	cppn := cppnFromJSON(t, 5, 2, `{"id":10,"from":0,"to":5,"weight":1},{"id":11,"from":4,"to":6,"weight":0.5}`)
+	net := substrate.Decode(cppn)
+	// The middle synapse is below the threshold, and the outer two are at the maximum weight, so this is -3*1 + 0*5 + 3*2 + 0.5*3
+	assertEq(t, net.Forward([]float64{1, 5, 2})[0], 4.5, "thresholded weights")
+
+	// The expression output is the x position of the source neuron, so only the synapses from the right two neurons are expressed
+	substrate = substrate.WithExpression(0.2, 2, true)
+	assertEq(t, substrate.NumCPPNOutputs(), 3, "number of cppn outputs with expression")
+	cppn = cppnFromJSON(t, 5, 3, `{"id":10,"from":0,"to":5,"weight":1},{"id":11,"from":4,"to":6,"weight":0.5},{"id":12,"from":0,"to":7,"weight":1}`)
+	net = substrate.Decode(cppn)
+	if out := net.Forward([]float64{1, 5, 2})[0]; out < 0.2*5+2*2+1-1e-9 || out > 0.2*5+2*2+1+1e-9 {
+		t.Fatalf("expected output %v, got %v", 0.2*5+2*2+1, out)
+	}
+}
+
+// Check that the grid positions are spread over [-1, 1]
+func TestSubstrateGrid(t *testing.T) {
+	grid := SubstrateGrid(3, 2, 0.5)
+	assertEq(t, len(grid), 6, "number of positions")
+	assertEq(t, grid[0][0], -1.0, "first x")
+	assertEq(t, grid[5][0], 1.0, "last x")
+	assertEq(t, grid[4][0], 0.0, "middle x")
+	assertEq(t, grid[4][1], 1.0, "second row y")
+	assertEq(t, grid[4][2], 0.5, "z")
+	substrate := NewSubstrate([][][]float64{grid, SubstrateGrid(1, 1, 1)}, Relu, Sigmoid)
+	assertEq(t, substrate.NumCPPNInputs(), 7, "number of cppn inputs in 3D")
+	assertEq(t, substrate.Shape()[0], 6, "input layer size")
+}
+
+// Check that evolving CPPNs can solve XOR through a substrate
+func TestHyperNeatXOR(t *testing.T) {
+	substrate := NewSubstrate([][][]float64{
+		{{-1, -1}, {0, -1}, {1, -1}},
+		{{-1, 0}, {-0.5, 0}, {0, 0}, {0.5, 0}, {1, 0}},
+		{{0, 1}},
+	}, Tanh, Sigmoid)
+	rng := NewRand(0)
+	counter := NewCounter()
+	originalGt := NewNeatGenotype(counter, substrate.NumCPPNInputs(), substrate.NumCPPNOutputs(), Tanh)
+	mut := NewNeatMutationStd(rng, counter, AllCPPNActivations, 1, 0, 0.5, 2, 0, 0.5, 0.2, 0.4, 3)
+	reprod := NewTwoPhaseReproduction(NewNeatCrossoverSimple(rng), mut)
+	pop := NewSimplePopulation(func() *NeatGenotype {
+		gt := Clone(originalGt)
+		gt.AddRandomSynapse(rng, counter, 0.3, false)
+		return gt
+	}, 100, NewTournamentSelection[*NeatGenotype](rng, 3), reprod)
+	testWithXORDataset(t, Population[*NeatGenotype](pop), func(g *NeatGenotype) Forwarder { return substrate.Decode(g) })
+}
@@ -0,0 +1,181 @@
+package goevo
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// IslandPopulation is a population made of several independent sub-populations (islands), which can each be any [Population].
+// Every few generations, copies of some agents from each island migrate to other islands along a [MigrationTopology],
+// replacing newly bred children there.
+//
+// Islands are advanced in parallel, so islands must not share selections, reproductions, or random sources.
+// Islands may use completely different selection and reproduction strategies to each other.
+type IslandPopulation[T any] struct {
+	rng         *rand.Rand
+	islands     []Population[T]
+	topology    MigrationTopology
+	interval    int
+	numMigrants int
+	migrateBest bool
+	generation  int
+}
+
+// NewIslandPopulation creates a new IslandPopulation from the given islands.
+// Every interval generations, each island sends numMigrants agents to each of its destinations in the topology.
+// If migrateBest is true the fittest agents migrate, otherwise they are chosen at random.
+//
+// Migrants are copied into children that the next generation of the destination island has just created,
+// so the agents of an evaluated generation are never modified. Each island's [Population.All] (or [IncrementalPopulation.Unevaluated])
+// must return the agents that the island actually uses (as all populations in this package do).
+func NewIslandPopulation[T any](
+	rng *rand.Rand,
+	islands []Population[T],
+	topology MigrationTopology,
+	interval int,
+	numMigrants int,
+	migrateBest bool,
+) *IslandPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if len(islands) == 0 {
+		panic("must have at least one island")
+	}
+	if topology == nil {
+		panic("cannot have nil topology")
+	}
+	if interval <= 0 {
+		panic("must have a migration interval of at least 1")
+	}
+	if numMigrants < 0 {
+		panic("cannot have a negative number of migrants")
+	}
+	return &IslandPopulation[T]{
+		rng:         rng,
+		islands:     slices.Clone(islands),
+		topology:    topology,
+		interval:    interval,
+		numMigrants: numMigrants,
+		migrateBest: migrateBest,
+	}
+}
+
+// NextGeneration implements [Population].
+// If this is a migration generation, migrants are chosen using the current fitnesses, then every island creates its next generation,
+// and the migrants replace children in the next generations of their destinations.
+func (p *IslandPopulation[T]) NextGeneration() Population[T] {
+	generation := p.generation + 1
+	var incoming [][]Agent[T]
+	if generation%p.interval == 0 {
+		incoming = p.chooseIncoming()
+	}
+	islands := make([]Population[T], len(p.islands))
+	wg := &sync.WaitGroup{}
+	for i, island := range p.islands {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			islands[i] = island.NextGeneration()
+		}()
+	}
+	wg.Wait()
+	for i := range incoming {
+		receiveMigrants(p.islands[i], islands[i], incoming[i])
+	}
+	return &IslandPopulation[T]{
+		rng:         p.rng,
+		islands:     islands,
+		topology:    p.topology,
+		interval:    p.interval,
+		numMigrants: p.numMigrants,
+		migrateBest: p.migrateBest,
+		generation:  generation,
+	}
+}
+
+// chooseIncoming returns copies of the migrants that each island will receive.
+// All migrants are chosen before any are moved, so the order of the islands does not matter.
+func (p *IslandPopulation[T]) chooseIncoming() [][]Agent[T] {
+	incoming := make([][]Agent[T], len(p.islands))
+	for i, island := range p.islands {
+		migrants := p.chooseMigrants(island.All())
+		for _, d := range p.topology.Destinations(i, len(p.islands)) {
+			for _, m := range migrants {
+				incoming[d] = append(incoming[d], *m)
+			}
+		}
+	}
+	return incoming
+}
+
+// receiveMigrants copies the migrants into the children that next has just bred from prev, starting with the last child.
+// Only agents that are not in prev are replaced, so no agent of prev (which may be referenced elsewhere) is modified.
+func receiveMigrants[T any](prev, next Population[T], migrants []Agent[T]) {
+	old := make(map[*Agent[T]]bool)
+	for _, a := range prev.All() {
+		old[a] = true
+	}
+	candidates := next.All()
+	if ip, ok := next.(IncrementalPopulation[T]); ok {
+		candidates = ip.Unevaluated()
+	}
+	children := make([]*Agent[T], 0, len(candidates))
+	for _, a := range candidates {
+		if !old[a] {
+			children = append(children, a)
+		}
+	}
+	for j := range min(len(children), len(migrants)) {
+		migrant := migrants[j]
+		migrant.Genotype = cloneIfCloneable(migrant.Genotype)
+		*children[len(children)-1-j] = migrant
+	}
+}
+
+// chooseMigrants returns the agents that should migrate from an island.
+func (p *IslandPopulation[T]) chooseMigrants(agents []*Agent[T]) []*Agent[T] {
+	n := min(p.numMigrants, len(agents))
+	if p.migrateBest {
+		sorted := slices.Clone(agents)
+		sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Fitness > sorted[b].Fitness })
+		return sorted[:n]
+	}
+	migrants := make([]*Agent[T], n)
+	for i, j := range p.rng.Perm(len(agents))[:n] {
+		migrants[i] = agents[j]
+	}
+	return migrants
+}
+
+// All implements [Population].
+// It returns the agents of every island, in island order.
+func (p *IslandPopulation[T]) All() []*Agent[T] {
+	all := make([]*Agent[T], 0)
+	for _, island := range p.islands {
+		all = append(all, island.All()...)
+	}
+	return all
+}
+
+// Unevaluated implements [IncrementalPopulation].
+// It returns the unevaluated agents of every island, in island order, including children that have been replaced by migrants.
+// Islands that are not an [IncrementalPopulation] return all of their agents.
+func (p *IslandPopulation[T]) Unevaluated() []*Agent[T] {
+	unevaluated := make([]*Agent[T], 0)
+	for _, island := range p.islands {
+		if ip, ok := island.(IncrementalPopulation[T]); ok {
+			unevaluated = append(unevaluated, ip.Unevaluated()...)
+		} else {
+			unevaluated = append(unevaluated, island.All()...)
+		}
+	}
+	return unevaluated
+}
+
+// Islands returns each island's current population.
+func (p *IslandPopulation[T]) Islands() []Population[T] {
+	return slices.Clone(p.islands)
+}
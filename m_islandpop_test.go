@@ -0,0 +1,77 @@
+package goevo
+
+import (
+	"slices"
+	"testing"
+)
+
+// Check that each topology sends migrants to the expected islands
+func TestMigrationTopologies(t *testing.T) {
+	assertEq(t, slices.Equal(NewRingTopology().Destinations(3, 4), []int{0}), true, "ring wraps around")
+	assertEq(t, slices.Equal(NewFullyConnectedTopology().Destinations(1, 3), []int{0, 2}), true, "fully connected")
+	custom := NewCustomTopology([][]int{{1, 2}, {}, {0}})
+	assertEq(t, slices.Equal(custom.Destinations(0, 3), []int{1, 2}), true, "custom")
+	assertEq(t, len(custom.Destinations(1, 3)), 0, "custom no destinations")
+}
+
+// Check that the best agent of each island replaces a child in the next generation of the next island in the ring,
+// without modifying the agents of the previous generation
+func TestIslandMigration(t *testing.T) {
+	rng := NewRand(0)
+	newIsland := func(offset int) Population[int] {
+		agents := make([]*Agent[int], 5)
+		for i := range agents {
+			agents[i] = &Agent[int]{Genotype: offset + i, Fitness: float64(offset + i)}
+		}
+		return NewSimplePopulationFrom(agents, NewEliteSelection[int](), NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{}))
+	}
+	pop := NewIslandPopulation(rng, []Population[int]{newIsland(0), newIsland(100)}, NewRingTopology(), 1, 1, true)
+	next := NextGeneration(pop).Islands()
+	assertEq(t, next[0].All()[4].Genotype, 104, "migrant from island 1")
+	assertEq(t, next[0].All()[4].Fitness, 104.0, "migrant fitness")
+	assertEq(t, next[0].All()[0].Genotype, 4, "child of island 0")
+	assertEq(t, next[1].All()[4].Genotype, 4, "migrant from island 0")
+	for i, island := range pop.Islands() {
+		for j, a := range island.All() {
+			assertEq(t, a.Genotype, i*100+j, "previous generation unchanged")
+		}
+	}
+	assertEq(t, len(pop.All()), 10, "num agents")
+}
+
+// Check that an island model can solve a simple problem
+func TestIslandPopulation(t *testing.T) {
+	rng := NewRand(0)
+	islands := make([]Population[*ArrayGenotype[float64]], 4)
+	for i := range islands {
+		irng := DeriveRand(rng)
+		mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(irng, 0.0, 0.05), 0.1)
+		reprod := NewTwoPhaseReproduction(NewArrayCrossoverUniform[float64](irng), mut)
+		islands[i] = NewSimplePopulation(func() *ArrayGenotype[float64] {
+			return NewArrayGenotype(5, NewGeneratorNormal(irng, 0.0, 0.5))
+		}, 25, NewTournamentSelection[*ArrayGenotype[float64]](irng, 3), reprod)
+	}
+	pop := NewIslandPopulation(rng, islands, NewRingTopology(), 5, 2, false)
+	fitness := func(g *ArrayGenotype[float64]) float64 {
+		total := 0.0
+		for i := range g.Len() {
+			total -= (g.At(i) - 1) * (g.At(i) - 1)
+		}
+		return total
+	}
+	best, _ := NewRunner(fitness, 4, NewStopTargetFitness(-0.01), NewStopMaxGenerations(1000)).Run(pop)
+	if best.Fitness < -0.01 {
+		t.Fatalf("island population did not converge, best fitness %v", best.Fitness)
+	}
+}
+
+// Check that the runner only evaluates the new children of islands of steady state populations, including those replaced by migrants
+func TestIslandPopulationIncremental(t *testing.T) {
+	islands := make([]Population[*ArrayGenotype[float64]], 3)
+	for i := range islands {
+		islands[i] = setupSteadyStateTestStuff(uint64(i))
+	}
+	pop := NewIslandPopulation(NewRand(0), islands, NewRingTopology(), 2, 1, true)
+	_, summary := NewRunner(steadyStateTestFitness, 4, NewStopMaxGenerations(20)).Run(pop)
+	assertEq(t, summary.Evaluations, 3*30+(summary.Generations-1)*3*2, "evaluations")
+}
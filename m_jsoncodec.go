@@ -0,0 +1,24 @@
+package goevo
+
+import "encoding/json"
+
+// jsonCodec is a [Codec] that uses the encoding/json package.
+// It works with any genotype that can be marshalled to JSON, such as [NeatGenotype].
+type jsonCodec[T any] struct{}
+
+// NewJSONCodec creates a new [Codec] that encodes genotypes as JSON.
+func NewJSONCodec[T any]() Codec[T] {
+	return &jsonCodec[T]{}
+}
+
+// Encode implements [Codec].
+func (c *jsonCodec[T]) Encode(g T) ([]byte, error) {
+	return json.Marshal(g)
+}
+
+// Decode implements [Codec].
+func (c *jsonCodec[T]) Decode(bs []byte) (T, error) {
+	var g T
+	err := json.Unmarshal(bs, &g)
+	return g, err
+}
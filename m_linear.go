@@ -0,0 +1,430 @@
+package goevo
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LinearMachine describes the register machine that the instructions of a [LinearGenotype] run on.
+//
+// There are numRegisters calculation registers, which start at 0 and can be read and written, and the inputs, which can only be read.
+// Each instruction sets a calculation register to the result of a function of one or two operands, such as r0 = add(r1, x0).
+// The first numOutputs calculation registers are the outputs once every instruction has run.
+// If constants is not nil, the last operand of an instruction is a constant drawn from it with the given chance.
+type LinearMachine struct {
+	numInputs      int
+	numOutputs     int
+	numRegisters   int
+	functions      []TreeFunction
+	constants      Generator[float64]
+	constantChance float64
+}
+
+// NewLinearMachine creates a new [LinearMachine].
+// The functions may be any [TreeFunction] with an arity of 1 or 2, such as [TreeAdd].
+func NewLinearMachine(numInputs, numOutputs, numRegisters int, constants Generator[float64], constantChance float64, functions ...TreeFunction) *LinearMachine {
+	if numInputs <= 0 || numOutputs <= 0 {
+		panic("must have at least one input and one output")
+	}
+	if numRegisters < numOutputs {
+		panic("must have at least as many registers as outputs")
+	}
+	if constantChance < 0 || constantChance > 1 {
+		panic("cannot have constant chance out of range 0-1")
+	}
+	if len(functions) == 0 {
+		panic("must have at least one function")
+	}
+	for _, f := range functions {
+		if f.Arity != 1 && f.Arity != 2 {
+			panic("functions must have an arity of 1 or 2")
+		}
+		if f.Apply == nil {
+			panic("cannot have nil function")
+		}
+	}
+	if constants == nil {
+		constantChance = 0
+	}
+	return &LinearMachine{
+		numInputs:      numInputs,
+		numOutputs:     numOutputs,
+		numRegisters:   numRegisters,
+		functions:      slices.Clone(functions),
+		constants:      constants,
+		constantChance: constantChance,
+	}
+}
+
+// linearInstruction is a single instruction of a [LinearGenotype], which sets dest to the result of the function of its operands.
+type linearInstruction struct {
+	function int
+	dest     int
+	// operands are the registers the function reads. Calculation registers come first, then the inputs, and -1 is the constant.
+	// Only the first arity operands are used.
+	operands [2]int
+	constant float64
+}
+
+// randomOperand returns a random calculation register or input.
+func (m *LinearMachine) randomOperand(rng *rand.Rand) int {
+	return rng.IntN(m.numRegisters + m.numInputs)
+}
+
+// randomInstruction returns a new random instruction.
+func (m *LinearMachine) randomInstruction(rng *rand.Rand) linearInstruction {
+	ins := linearInstruction{
+		function: rng.IntN(len(m.functions)),
+		dest:     rng.IntN(m.numRegisters),
+	}
+	for o := range ins.operands {
+		ins.operands[o] = m.randomOperand(rng)
+	}
+	m.maybeConstant(rng, &ins)
+	return ins
+}
+
+// maybeConstant makes the last operand of a two operand instruction a new constant, with the constant chance.
+// There is never more than one constant, as an instruction of only constants is always the same.
+func (m *LinearMachine) maybeConstant(rng *rand.Rand, ins *linearInstruction) {
+	if m.functions[ins.function].Arity == 2 && rng.Float64() < m.constantChance {
+		ins.operands[1] = -1
+		ins.constant = m.constants.Next()
+	}
+}
+
+// LinearGenotype is a genotype for linear genetic programming, where the genotype is a variable-length program of register machine instructions.
+// See [LinearMachine] for how the instructions run.
+//
+// Many instructions usually have no effect on the outputs, because the register they write is overwritten or never read.
+// These introns are found by [LinearGenotype.EffectiveInstructions], and are left out of the program created by [LinearGenotype.Build].
+// The genotype can also be run directly with [LinearGenotype.Forward], but building it once is faster when running it on many inputs.
+type LinearGenotype struct {
+	machine      *LinearMachine
+	instructions []linearInstruction
+}
+
+// NewLinearGenotype creates a new [LinearGenotype] with a random length between minLength and maxLength, and random instructions.
+func NewLinearGenotype(rng *rand.Rand, machine *LinearMachine, minLength, maxLength int) *LinearGenotype {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if machine == nil {
+		panic("cannot have nil machine")
+	}
+	if minLength <= 0 || maxLength < minLength {
+		panic("min length must be at least 1, and max length must be at least min length")
+	}
+	instructions := make([]linearInstruction, minLength+rng.IntN(maxLength-minLength+1))
+	for i := range instructions {
+		instructions[i] = machine.randomInstruction(rng)
+	}
+	return &LinearGenotype{
+		machine:      machine,
+		instructions: instructions,
+	}
+}
+
+// Len returns the number of instructions, including introns.
+func (g *LinearGenotype) Len() int {
+	return len(g.instructions)
+}
+
+// effective returns whether each instruction affects the outputs.
+// It works backwards from the end of the program, keeping track of which calculation registers are still read by a later effective instruction or an output.
+func (g *LinearGenotype) effective() []bool {
+	effective := make([]bool, len(g.instructions))
+	needed := make([]bool, g.machine.numRegisters)
+	for r := range g.machine.numOutputs {
+		needed[r] = true
+	}
+	for i := len(g.instructions) - 1; i >= 0; i-- {
+		ins := g.instructions[i]
+		if !needed[ins.dest] {
+			continue
+		}
+		effective[i] = true
+		needed[ins.dest] = false
+		for _, o := range ins.operands[:g.machine.functions[ins.function].Arity] {
+			if o >= 0 && o < g.machine.numRegisters {
+				needed[o] = true
+			}
+		}
+	}
+	return effective
+}
+
+// EffectiveInstructions returns the index of every instruction that affects the outputs.
+// Every other instruction is an intron, which can be changed without changing the behaviour of the program.
+func (g *LinearGenotype) EffectiveInstructions() []int {
+	var indices []int
+	for i, e := range g.effective() {
+		if e {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// Clone implements [Cloneable].
+// The clone shares the same [LinearMachine].
+func (g *LinearGenotype) Clone() any {
+	return &LinearGenotype{
+		machine:      g.machine,
+		instructions: slices.Clone(g.instructions),
+	}
+}
+
+// Validate implements [Validateable].
+// It checks that every instruction refers to a valid function, registers, and inputs.
+func (g *LinearGenotype) Validate() error {
+	if g.machine == nil {
+		return fmt.Errorf("program has no machine")
+	}
+	if len(g.instructions) == 0 {
+		return fmt.Errorf("program has no instructions")
+	}
+	for i, ins := range g.instructions {
+		if ins.function < 0 || ins.function >= len(g.machine.functions) {
+			return fmt.Errorf("instruction %v has invalid function %v", i, ins.function)
+		}
+		if ins.dest < 0 || ins.dest >= g.machine.numRegisters {
+			return fmt.Errorf("instruction %v has invalid destination %v", i, ins.dest)
+		}
+		for o, op := range ins.operands[:g.machine.functions[ins.function].Arity] {
+			if op == -1 && (o == 0 || g.machine.constants == nil) {
+				return fmt.Errorf("instruction %v has an invalid constant operand", i)
+			}
+			if op < -1 || op >= g.machine.numRegisters+g.machine.numInputs {
+				return fmt.Errorf("instruction %v has invalid operand %v", i, op)
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the program with one instruction on each line, such as "r0 = add(r1, x0)".
+// Introns are prefixed with "#".
+func (g *LinearGenotype) String() string {
+	operand := func(ins linearInstruction, op int) string {
+		switch {
+		case op == -1:
+			return strconv.FormatFloat(ins.constant, 'g', 4, 64)
+		case op < g.machine.numRegisters:
+			return fmt.Sprintf("r%d", op)
+		default:
+			return fmt.Sprintf("x%d", op-g.machine.numRegisters)
+		}
+	}
+	lines := make([]string, len(g.instructions))
+	for i, e := range g.effective() {
+		ins := g.instructions[i]
+		f := g.machine.functions[ins.function]
+		args := make([]string, f.Arity)
+		for o, op := range ins.operands[:f.Arity] {
+			args[o] = operand(ins, op)
+		}
+		lines[i] = fmt.Sprintf("r%d = %s(%s)", ins.dest, f.Name, strings.Join(args, ", "))
+		if !e {
+			lines[i] = "# " + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Build implements [Buildable], creating a program that only runs the effective instructions.
+func (g *LinearGenotype) Build() Forwarder {
+	p := &LinearPhenotype{
+		machine:   g.machine,
+		registers: make([]float64, g.machine.numRegisters),
+	}
+	for _, i := range g.EffectiveInstructions() {
+		p.instructions = append(p.instructions, g.instructions[i])
+	}
+	return p
+}
+
+// Forward implements [Forwarder] by building the program and running it on the inputs.
+// To run the same program on many inputs, use [LinearGenotype.Build] once instead, so the introns are only found once.
+func (g *LinearGenotype) Forward(inputs []float64) []float64 {
+	return g.Build().Forward(inputs)
+}
+
+// LinearPhenotype is a program built from a [LinearGenotype], which only contains the effective instructions.
+type LinearPhenotype struct {
+	machine      *LinearMachine
+	instructions []linearInstruction
+	registers    []float64
+}
+
+// Forward implements [Forwarder] by running the instructions on the inputs, then returning the output registers.
+func (p *LinearPhenotype) Forward(inputs []float64) []float64 {
+	if len(inputs) != p.machine.numInputs {
+		panic("incorrect number of inputs")
+	}
+	clear(p.registers)
+	args := make([]float64, 2)
+	for _, ins := range p.instructions {
+		f := p.machine.functions[ins.function]
+		for o, op := range ins.operands[:f.Arity] {
+			switch {
+			case op == -1:
+				args[o] = ins.constant
+			case op < p.machine.numRegisters:
+				args[o] = p.registers[op]
+			default:
+				args[o] = inputs[op-p.machine.numRegisters]
+			}
+		}
+		p.registers[ins.dest] = f.Apply(args[:f.Arity])
+	}
+	return slices.Clone(p.registers[:p.machine.numOutputs])
+}
+
+// linearMutationInstruction is a mutation that changes part of a single instruction.
+type linearMutationInstruction struct {
+	rng           *rand.Rand
+	effectiveOnly bool
+}
+
+// NewLinearMutationInstruction creates a new mutation for [LinearGenotype]s that changes one part of a random instruction:
+// either its function, its destination, one of its operands, or its constant.
+// If effectiveOnly is true, only effective instructions are mutated (if there are any), so the mutation almost always changes the behaviour of the program.
+func NewLinearMutationInstruction(rng *rand.Rand, effectiveOnly bool) Mutation[*LinearGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	return &linearMutationInstruction{
+		rng:           rng,
+		effectiveOnly: effectiveOnly,
+	}
+}
+
+// Mutate implements [Mutation].
+func (m *linearMutationInstruction) Mutate(g *LinearGenotype) {
+	i := m.rng.IntN(len(g.instructions))
+	if m.effectiveOnly {
+		if effective := g.EffectiveInstructions(); len(effective) > 0 {
+			i = effective[m.rng.IntN(len(effective))]
+		}
+	}
+	ins := &g.instructions[i]
+	machine := g.machine
+	switch m.rng.IntN(3) {
+	case 0:
+		// Both operands are always valid, so the new function can have a different arity
+		ins.function = m.rng.IntN(len(machine.functions))
+	case 1:
+		ins.dest = m.rng.IntN(machine.numRegisters)
+	case 2:
+		o := m.rng.IntN(machine.functions[ins.function].Arity)
+		if o == 1 && ins.operands[1] == -1 && m.rng.IntN(2) == 0 {
+			ins.constant = machine.constants.Next()
+			return
+		}
+		ins.operands[o] = machine.randomOperand(m.rng)
+		if o == 1 {
+			machine.maybeConstant(m.rng, ins)
+		}
+	}
+}
+
+// linearMutationMacro is a mutation that inserts or deletes a whole instruction.
+type linearMutationMacro struct {
+	rng          *rand.Rand
+	insertChance float64
+	minLength    int
+	maxLength    int
+}
+
+// NewLinearMutationMacro creates a new mutation for [LinearGenotype]s that inserts a random instruction at a random position with insertChance,
+// and otherwise deletes a random instruction. The length of the program is always kept between minLength and maxLength.
+func NewLinearMutationMacro(rng *rand.Rand, insertChance float64, minLength, maxLength int) Mutation[*LinearGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if insertChance < 0 || insertChance > 1 {
+		panic("cannot have insert chance out of range 0-1")
+	}
+	if minLength <= 0 || maxLength < minLength {
+		panic("min length must be at least 1, and max length must be at least min length")
+	}
+	return &linearMutationMacro{
+		rng:          rng,
+		insertChance: insertChance,
+		minLength:    minLength,
+		maxLength:    maxLength,
+	}
+}
+
+// Mutate implements [Mutation].
+func (m *linearMutationMacro) Mutate(g *LinearGenotype) {
+	insert := m.rng.Float64() < m.insertChance
+	if len(g.instructions) >= m.maxLength {
+		insert = false
+	} else if len(g.instructions) <= m.minLength {
+		insert = true
+	}
+	if insert {
+		i := m.rng.IntN(len(g.instructions) + 1)
+		g.instructions = slices.Insert(g.instructions, i, g.machine.randomInstruction(m.rng))
+	} else if len(g.instructions) > 1 {
+		i := m.rng.IntN(len(g.instructions))
+		g.instructions = slices.Delete(g.instructions, i, i+1)
+	}
+}
+
+// linearCrossoverTwoPoint is a crossover that swaps a segment of one parent for a segment of the other.
+type linearCrossoverTwoPoint struct {
+	rng       *rand.Rand
+	maxLength int
+}
+
+// NewLinearCrossoverTwoPoint creates a new two-point crossover for [LinearGenotype]s, which requires two parents.
+// A random segment of the first parent is replaced by a random segment of the second, which may have a different length.
+// If the child would be longer than maxLength, new segments are tried a few times, and if they all fail the child is a copy of the first parent.
+func NewLinearCrossoverTwoPoint(rng *rand.Rand, maxLength int) Crossover[*LinearGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if maxLength <= 0 {
+		panic("max length must be at least 1")
+	}
+	return &linearCrossoverTwoPoint{
+		rng:       rng,
+		maxLength: maxLength,
+	}
+}
+
+// Crossover implements [Crossover].
+func (c *linearCrossoverTwoPoint) Crossover(gs []*LinearGenotype) *LinearGenotype {
+	if len(gs) != 2 {
+		panic("two point crossover requires exactly 2 parents")
+	}
+	a, b := gs[0], gs[1]
+	if a.machine != b.machine {
+		panic("parents must have the same machine for two point crossover")
+	}
+	for range 10 {
+		startA := c.rng.IntN(len(a.instructions))
+		endA := startA + 1 + c.rng.IntN(len(a.instructions)-startA)
+		startB := c.rng.IntN(len(b.instructions))
+		endB := startB + 1 + c.rng.IntN(len(b.instructions)-startB)
+		if len(a.instructions)-(endA-startA)+(endB-startB) > c.maxLength {
+			continue
+		}
+		return &LinearGenotype{
+			machine:      a.machine,
+			instructions: slices.Concat(a.instructions[:startA], b.instructions[startB:endB], a.instructions[endA:]),
+		}
+	}
+	return Clone(a)
+}
+
+// NumParents implements [Crossover].
+func (c *linearCrossoverTwoPoint) NumParents() int {
+	return 2
+}
@@ -0,0 +1,81 @@
+package goevo
+
+import (
+	"slices"
+	"testing"
+)
+
+// Check that introns are found and skipped, and that a hand-written program runs correctly
+func TestLinearGenotype(t *testing.T) {
+	machine := NewLinearMachine(2, 1, 3, NewGeneratorNormal(NewRand(0), 0.0, 1.0), 0.2, TreeAdd, TreeMul, TreeSin)
+	// Registers are r0, r1, r2, then the inputs are x0 (3) and x1 (4)
+	g := &LinearGenotype{machine: machine, instructions: []linearInstruction{
+		{function: 0, dest: 1, operands: [2]int{3, 4}},               // r1 = add(x0, x1)
+		{function: 2, dest: 2, operands: [2]int{3, 0}},               // r2 = sin(x0), which is never read
+		{function: 1, dest: 0, operands: [2]int{1, -1}, constant: 2}, // r0 = mul(r1, 2)
+		{function: 0, dest: 1, operands: [2]int{0, 0}},               // r1 = add(r0, r0), which is after r1 is last read
+		{function: 0, dest: 0, operands: [2]int{0, 4}},               // r0 = add(r0, x1)
+	}}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, slices.Equal(g.EffectiveInstructions(), []int{0, 2, 4}), true, "effective instructions")
+	// ((1 + 2) * 2) + 2
+	net := g.Build()
+	assertEq(t, net.Forward([]float64{1, 2})[0], 8.0, "forward")
+	assertEq(t, net.Forward([]float64{0, 1})[0], 3.0, "forward again")
+	assertEq(t, len(net.(*LinearPhenotype).instructions), 3, "only effective instructions are compiled")
+	assertEq(t, g.Forward([]float64{1, 2})[0], 8.0, "forward genotype")
+	assertEq(t, g.String(), "r1 = add(x0, x1)\n# r2 = sin(x0)\nr0 = mul(r1, 2)\n# r1 = add(r0, r0)\nr0 = add(r0, x1)", "string")
+
+	c := Clone(g)
+	c.instructions[0].operands[0] = -1
+	if c.Validate() == nil {
+		t.Fatal("constant first operand was valid")
+	}
+	assertEq(t, g.instructions[0].operands[0], 3, "clone is deep")
+}
+
+// Check that the operators keep programs valid and within the length limits
+func TestLinearOperators(t *testing.T) {
+	rng := NewRand(0)
+	machine := NewLinearMachine(3, 2, 4, NewGeneratorNormal(rng, 0.0, 1.0), 0.3, TreeAdd, TreeSub, TreeMul, TreeSin)
+	programs := make([]*LinearGenotype, 20)
+	for i := range programs {
+		programs[i] = NewLinearGenotype(rng, machine, 2, 10)
+		if programs[i].Len() < 2 || programs[i].Len() > 10 {
+			t.Fatalf("program has length %v outside of 2-10", programs[i].Len())
+		}
+	}
+	const minLength, maxLength = 2, 15
+	crossover := NewLinearCrossoverTwoPoint(rng, maxLength)
+	mutations := []Mutation[*LinearGenotype]{
+		NewLinearMutationInstruction(rng, false),
+		NewLinearMutationInstruction(rng, true),
+		NewLinearMutationMacro(rng, 0.6, minLength, maxLength),
+	}
+	for i := range 500 {
+		a, b := programs[rng.IntN(len(programs))], programs[rng.IntN(len(programs))]
+		child := crossover.Crossover([]*LinearGenotype{a, b})
+		mutations[i%len(mutations)].Mutate(child)
+		if err := child.Validate(); err != nil {
+			t.Fatalf("invalid program %v: %v", child, err)
+		}
+		if child.Len() > maxLength {
+			t.Fatalf("program has length %v above %v", child.Len(), maxLength)
+		}
+		programs[rng.IntN(len(programs))] = child
+	}
+}
+
+// Check that linear genetic programming can solve XOR, growing and shrinking programs with macro mutations
+func TestLinearXOR(t *testing.T) {
+	rng := NewRand(0)
+	machine := NewLinearMachine(3, 1, 4, NewGeneratorNormal(rng, 0.0, 1.0), 0.2, TreeAdd, TreeSub, TreeMul, TreeDiv)
+	mut := NewLinearMutationMacro(rng, 0.5, 2, 20)
+	reprod := NewTwoPhaseReproduction(NewLinearCrossoverTwoPoint(rng, 20), mut)
+	pop := NewSimplePopulation(func() *LinearGenotype {
+		return NewLinearGenotype(rng, machine, 2, 10)
+	}, 100, NewTournamentSelection[*LinearGenotype](rng, 3), reprod)
+	testWithXORDataset(t, Population[*LinearGenotype](pop), nil)
+}
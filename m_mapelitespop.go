@@ -0,0 +1,186 @@
+package goevo
+
+import (
+	"maps"
+	"math"
+	"math/rand/v2"
+)
+
+// MapElitesPopulation is a quality-diversity population using the MAP-Elites algorithm.
+// The behaviour space is split into a grid of cells, and an archive keeps the best agent found in each cell.
+//
+// The agents returned by [MapElitesPopulation.All] are a batch of new agents waiting to be evaluated.
+// When the next generation is created, each agent in the batch is placed in the cell of its behaviour descriptor
+// if that cell is empty or it is fitter than the current elite, and then a new batch is bred from randomly chosen elites.
+//
+// [MapElitesPopulation.Archive], [MapElitesPopulation.Coverage], and [MapElitesPopulation.QDScore] also include the current batch,
+// so they should only be used once the batch has been evaluated, such as on the population returned by [Runner.RunContext].
+type MapElitesPopulation[T any] struct {
+	rng          *rand.Rand
+	descriptor   func(*Agent[T]) []float64
+	bins         []int
+	lower        []float64
+	upper        []float64
+	archive      map[int]*Agent[T]
+	batch        []*Agent[T]
+	batchCells   []int
+	reproduction Reproduction[T]
+}
+
+// NewMapElitesPopulation creates a new MapElitesPopulation with an empty archive, and a first batch of batchSize agents created by newGenotype.
+//
+// The descriptor is called once per evaluated agent, and must return a point in the behaviour space with one value per dimension.
+// Each dimension i is split into bins[i] equally sized cells between lower[i] and upper[i].
+// Descriptors outside of these bounds are placed in the nearest cell.
+func NewMapElitesPopulation[T any](
+	rng *rand.Rand,
+	newGenotype func() T,
+	batchSize int,
+	descriptor func(*Agent[T]) []float64,
+	bins []int,
+	lower []float64,
+	upper []float64,
+	reproduction Reproduction[T],
+) *MapElitesPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if descriptor == nil {
+		panic("cannot have nil descriptor")
+	}
+	if reproduction == nil {
+		panic("cannot have nil reproduction")
+	}
+	if batchSize <= 0 {
+		panic("must have a batch size of at least 1")
+	}
+	if len(bins) == 0 {
+		panic("must have at least one behaviour dimension")
+	}
+	if len(lower) != len(bins) || len(upper) != len(bins) {
+		panic("must have the same number of bins, lower bounds, and upper bounds")
+	}
+	for i := range bins {
+		if bins[i] <= 0 {
+			panic("must have at least one bin per dimension")
+		}
+		if upper[i] <= lower[i] {
+			panic("upper bounds must be greater than lower bounds")
+		}
+	}
+	batch := make([]*Agent[T], batchSize)
+	for i := range batch {
+		batch[i] = NewAgent(newGenotype())
+	}
+	return &MapElitesPopulation[T]{
+		rng:          rng,
+		descriptor:   descriptor,
+		bins:         bins,
+		lower:        lower,
+		upper:        upper,
+		archive:      make(map[int]*Agent[T]),
+		batch:        batch,
+		reproduction: reproduction,
+	}
+}
+
+// NextGeneration implements [Population].
+func (p *MapElitesPopulation[T]) NextGeneration() Population[T] {
+	archive := p.insertBatch()
+	cells := sortedKeys(archive)
+	batch := make([]*Agent[T], len(p.batch))
+	for i := range batch {
+		parents := make([]*Agent[T], p.reproduction.NumParents())
+		for j := range parents {
+			parents[j] = archive[cells[p.rng.IntN(len(cells))]]
+		}
+		batch[i] = NewAgent(ReproduceAgents(p.reproduction, parents))
+	}
+	return &MapElitesPopulation[T]{
+		rng:          p.rng,
+		descriptor:   p.descriptor,
+		bins:         p.bins,
+		lower:        p.lower,
+		upper:        p.upper,
+		archive:      archive,
+		batch:        batch,
+		reproduction: p.reproduction,
+	}
+}
+
+// insertBatch returns a copy of the archive with the current batch inserted into it.
+func (p *MapElitesPopulation[T]) insertBatch() map[int]*Agent[T] {
+	archive := maps.Clone(p.archive)
+	for i, cell := range p.cellsOfBatch() {
+		a := p.batch[i]
+		if elite, ok := archive[cell]; !ok || a.Fitness > elite.Fitness {
+			archive[cell] = a
+		}
+	}
+	return archive
+}
+
+// cellsOfBatch returns the cell of each agent in the current batch.
+// The descriptor is only called the first time, as the batch has been evaluated by then.
+func (p *MapElitesPopulation[T]) cellsOfBatch() []int {
+	if p.batchCells == nil {
+		p.batchCells = make([]int, len(p.batch))
+		for i, a := range p.batch {
+			p.batchCells[i] = p.CellOf(p.descriptor(a))
+		}
+	}
+	return p.batchCells
+}
+
+// All implements [Population].
+// It returns the current batch of agents, not the archive.
+func (p *MapElitesPopulation[T]) All() []*Agent[T] {
+	return p.batch
+}
+
+// CellOf returns the index of the cell that contains the behaviour descriptor.
+// Cells are numbered in row-major order, with the last dimension changing fastest.
+func (p *MapElitesPopulation[T]) CellOf(descriptor []float64) int {
+	if len(descriptor) != len(p.bins) {
+		panic("descriptor has the wrong number of dimensions")
+	}
+	cell := 0
+	for i, x := range descriptor {
+		b := int(math.Floor((x - p.lower[i]) / (p.upper[i] - p.lower[i]) * float64(p.bins[i])))
+		b = max(0, min(p.bins[i]-1, b))
+		cell = cell*p.bins[i] + b
+	}
+	return cell
+}
+
+// Archive returns the elite of every filled cell, keyed by cell index (see [MapElitesPopulation.CellOf]).
+// The current batch is included, so it must have been evaluated.
+func (p *MapElitesPopulation[T]) Archive() map[int]*Agent[T] {
+	return p.insertBatch()
+}
+
+// NumCells returns the total number of cells in the grid.
+func (p *MapElitesPopulation[T]) NumCells() int {
+	n := 1
+	for _, b := range p.bins {
+		n *= b
+	}
+	return n
+}
+
+// Coverage returns the fraction of cells that have an elite, between 0 and 1.
+// The current batch is included, so it must have been evaluated.
+func (p *MapElitesPopulation[T]) Coverage() float64 {
+	return float64(len(p.insertBatch())) / float64(p.NumCells())
+}
+
+// QDScore returns the sum of the fitnesses of all elites in the archive.
+// This only makes sense as a measure of quality and diversity if fitnesses are never negative.
+// The current batch is included, so it must have been evaluated.
+func (p *MapElitesPopulation[T]) QDScore() float64 {
+	total := 0.0
+	for _, a := range p.insertBatch() {
+		total += a.Fitness
+	}
+	return total
+}
@@ -0,0 +1,64 @@
+package goevo
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// Check that MAP-Elites fills a simple 2D behaviour grid, keeping the best agent in each cell
+func TestMapElitesCoverage(t *testing.T) {
+	rng := NewRand(0)
+	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0, 0.1), 1)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), mut)
+	// The behaviour is the first two genes, and the fitness is highest when the third gene is 0
+	descriptor := func(a *Agent[*ArrayGenotype[float64]]) []float64 {
+		return []float64{a.Genotype.At(0), a.Genotype.At(1)}
+	}
+	pop := NewMapElitesPopulation(rng, func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(3, NewGeneratorNormal(rng, 0.0, 0.1))
+	}, 20, descriptor, []int{5, 5}, []float64{-1, -1}, []float64{1, 1}, reprod)
+	assertEq(t, pop.NumCells(), 25, "num cells")
+	assertEq(t, pop.CellOf([]float64{-5, 0.9}), 4, "cell index")
+
+	fitness := func(g *ArrayGenotype[float64]) float64 { return 1 - math.Abs(g.At(2)) }
+	// The archive should include the last evaluated batch as soon as the runner has finished
+	_, first, _, _ := NewRunner(fitness, 4, NewStopMaxGenerations(1)).RunContext(context.Background(), pop)
+	if first.(*MapElitesPopulation[*ArrayGenotype[float64]]).Coverage() == 0 {
+		t.Fatal("first evaluated batch was not in the archive")
+	}
+	best, final, _, _ := NewRunner(fitness, 4, NewStopMaxGenerations(500)).RunContext(context.Background(), pop)
+	pop = final.(*MapElitesPopulation[*ArrayGenotype[float64]])
+	assertEq(t, pop.Archive()[pop.CellOf(descriptor(best))].Fitness, best.Fitness, "best agent is an elite")
+
+	assertEq(t, pop.Coverage(), 1.0, "coverage")
+	for cell, a := range pop.Archive() {
+		assertEq(t, pop.CellOf(descriptor(a)), cell, "elite cell")
+		if a.Fitness < 0.8 {
+			t.Fatalf("elite in cell %v has low fitness %v", cell, a.Fitness)
+		}
+	}
+	if pop.QDScore() < 0.8*25 {
+		t.Fatalf("qd score too low: %v", pop.QDScore())
+	}
+}
+
+// Check that the descriptor is only called once for each evaluated agent
+func TestMapElitesDescriptorCalls(t *testing.T) {
+	rng := NewRand(0)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0, 0.1), 1))
+	calls := 0
+	descriptor := func(a *Agent[*ArrayGenotype[float64]]) []float64 {
+		calls++
+		return []float64{a.Genotype.At(0)}
+	}
+	pop := NewMapElitesPopulation(rng, func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(2, NewGeneratorNormal(rng, 0.0, 0.1))
+	}, 10, descriptor, []int{5}, []float64{-1}, []float64{1}, reprod)
+	fitness := func(g *ArrayGenotype[float64]) float64 { return -math.Abs(g.At(1)) }
+	_, final, summary, _ := NewRunner(fitness, 1, NewStopMaxGenerations(20)).RunContext(context.Background(), pop)
+	final.(*MapElitesPopulation[*ArrayGenotype[float64]]).Archive()
+	final.(*MapElitesPopulation[*ArrayGenotype[float64]]).Coverage()
+	final.(*MapElitesPopulation[*ArrayGenotype[float64]]).QDScore()
+	assertEq(t, calls, summary.Evaluations, "descriptor calls")
+}
@@ -1,12 +1,13 @@
 package goevo
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"image"
 	"maps"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"slices"
 
 	"github.com/goccy/go-graphviz"
@@ -142,6 +143,50 @@ func (g *NeatGenotype) NumSynapses() int {
 	return len(g.weights)
 }
 
+// CompatibilityDistance returns the NEAT compatibility distance between this genotype and other, which is 0 for identical genotypes.
+// Synapses are lined up by their ID. Those that only one genotype has are excess if their ID is beyond the largest ID of the other genotype, and disjoint otherwise.
+// The distance is excessCoeff * excess / N + disjointCoeff * disjoint / N + weightCoeff * (mean absolute weight difference of matching synapses),
+// where N is the number of synapses in the larger genotype.
+func (g *NeatGenotype) CompatibilityDistance(other *NeatGenotype, excessCoeff, disjointCoeff, weightCoeff float64) float64 {
+	maxID := func(weights map[NeatSynapseID]float64) NeatSynapseID {
+		m := NeatSynapseID(-1)
+		for id := range weights {
+			if id > m {
+				m = id
+			}
+		}
+		return m
+	}
+	gMax, oMax := maxID(g.weights), maxID(other.weights)
+	excess, disjoint, matching := 0, 0, 0
+	weightDiff := 0.0
+	for id, w := range g.weights {
+		if ow, ok := other.weights[id]; ok {
+			matching++
+			weightDiff += math.Abs(w - ow)
+		} else if id > oMax {
+			excess++
+		} else {
+			disjoint++
+		}
+	}
+	for id := range other.weights {
+		if _, ok := g.weights[id]; ok {
+			continue
+		} else if id > gMax {
+			excess++
+		} else {
+			disjoint++
+		}
+	}
+	n := float64(max(1, max(len(g.weights), len(other.weights))))
+	distance := excessCoeff*float64(excess)/n + disjointCoeff*float64(disjoint)/n
+	if matching > 0 {
+		distance += weightCoeff * weightDiff / float64(matching)
+	}
+	return distance
+}
+
 // Validate runs as many checks as possible to check the genotype is valid.
 // It is really only designed to be used as part of a test suite to catch errors with the package.
 // This should never throw an error, but if it does either there is a bug in the package, or the user has somehow invalidated the genotype.
@@ -252,45 +297,53 @@ func (g *NeatGenotype) Validate() error {
 // AddRandomNeuron adds a new neuron to the genotype on a random forward synapse.
 // It will return false if there are no forward synapses to add to.
 // The new neuron will have a random activation function from the given list of activations.
-func (g *NeatGenotype) AddRandomNeuron(counter *Counter, activations ...Activation) bool {
+// The split synapse is replaced by two new synapses: one into the new neuron with the original weight, and one out of it with a weight of 1.
+// The IDs of the new neuron and synapses come from innovations, which may be a [*Counter] or a [NeatInnovationTracker].
+func (g *NeatGenotype) AddRandomNeuron(rng *rand.Rand, innovations NeatInnovations, activations ...Activation) bool {
 	if len(g.forwardSynapses) == 0 {
 		return false
 	}
 
 	// We only ever want to add nodes on forward synapses
-	sid := g.forwardSynapses[rand.Intn(len(g.forwardSynapses))]
+	sid := g.forwardSynapses[rng.IntN(len(g.forwardSynapses))]
 
 	ep := g.synapseEndpointLookup[sid]
+	weight := g.weights[sid]
 
-	newSid := NeatSynapseID(counter.Next())
-	newNid := NeatNeuronID(counter.Next())
+	newNid := innovations.SplitNeuronID(sid)
+	if _, ok := g.activations[newNid]; ok {
+		// This genotype has already split a synapse with the same ID, so the shared neuron ID is taken
+		newNid = NeatNeuronID(innovations.Next())
+	}
 
 	epa := NeatSynapseEP{ep.From, newNid}
 	epb := NeatSynapseEP{newNid, ep.To}
+	sida := innovations.SynapseID(epa)
+	sidb := innovations.SynapseID(epb)
 
-	// Swap the old connection for a, which will also retain the original weight
+	// Remove the old connection
+	idx := slices.Index(g.forwardSynapses, sid)
+	g.forwardSynapses = slices.Delete(g.forwardSynapses, idx, idx+1)
+	delete(g.weights, sid)
+	delete(g.synapseEndpointLookup, sid)
 	delete(g.endpointSynapseLookup, ep)
-	g.endpointSynapseLookup[epa] = sid
-	g.synapseEndpointLookup[sid] = epa
-	// Don't need to modify weights because weights[sid] is already there
+
+	// Create a new connection for a, which retains the original weight
+	g.endpointSynapseLookup[epa] = sida
+	g.synapseEndpointLookup[sida] = epa
+	g.weights[sida] = weight
 
 	// Create a new connection for b, with weight of 1 to minimise affect on behaviour
-	g.endpointSynapseLookup[epb] = newSid
-	g.synapseEndpointLookup[newSid] = epb
-	g.weights[newSid] = 1
+	g.endpointSynapseLookup[epb] = sidb
+	g.synapseEndpointLookup[sidb] = epb
+	g.weights[sidb] = 1
+
+	// Both new connections are forward, as the new neuron will be placed between the two original endpoints
+	g.forwardSynapses = append(g.forwardSynapses, sida, sidb)
 
 	// Find the two original endpoints orders, and also which was first and which was second
 	ao, bo := g.inverseNeuronOrder[ep.From], g.inverseNeuronOrder[ep.To]
 
-	// Add b to the index of its synapse class
-	if ao < bo {
-		g.forwardSynapses = append(g.forwardSynapses, newSid)
-	} else if ao > bo {
-		g.backwardSynapses = append(g.backwardSynapses, newSid)
-	} else {
-		g.selfSynapses = append(g.selfSynapses, newSid)
-	}
-
 	// Create a new neuron
 	firstO, secondO := ao, bo
 	if bo < ao {
@@ -328,7 +381,7 @@ func (g *NeatGenotype) AddRandomNeuron(counter *Counter, activations ...Activati
 		g.inverseNeuronOrder[g.neuronOrder[i]] = i
 	}
 	// Add the activation
-	g.activations[newNid] = activations[rand.Intn(len(activations))]
+	g.activations[newNid] = activations[rng.IntN(len(activations))]
 
 	return true
 }
@@ -337,11 +390,12 @@ func (g *NeatGenotype) AddRandomNeuron(counter *Counter, activations ...Activati
 // It will return false if it failed to find a place to put the synapse after 10 tries.
 // The synapse will have a random weight from a normal distribution with the given standard deviation.
 // If recurrent is true, the synapse will be recurrent, otherwise it will not.
-func (g *NeatGenotype) AddRandomSynapse(counter *Counter, weightStd float64, recurrent bool) bool {
+// The ID of the new synapse comes from innovations, which may be a [*Counter] or a [NeatInnovationTracker].
+func (g *NeatGenotype) AddRandomSynapse(rng *rand.Rand, innovations NeatInnovations, weightStd float64, recurrent bool) bool {
 	// Almost always find a new connection after 10 tries
 	for i := 0; i < 10; i++ {
-		ao := rand.Intn(len(g.neuronOrder))
-		bo := rand.Intn(len(g.neuronOrder))
+		ao := rng.IntN(len(g.neuronOrder))
+		bo := rng.IntN(len(g.neuronOrder))
 		if ao == bo && !recurrent {
 			continue // No self connections if non recurrent
 		}
@@ -356,10 +410,10 @@ func (g *NeatGenotype) AddRandomSynapse(counter *Counter, weightStd float64, rec
 		if _, ok := g.endpointSynapseLookup[ep]; ok {
 			continue // This connection already exists, try to find another
 		}
-		sid := NeatSynapseID(counter.Next())
+		sid := innovations.SynapseID(ep)
 		g.endpointSynapseLookup[ep] = sid
 		g.synapseEndpointLookup[sid] = ep
-		g.weights[sid] = clamp(rand.NormFloat64()*weightStd, -g.maxSynapseValue, g.maxSynapseValue)
+		g.weights[sid] = clamp(rng.NormFloat64()*weightStd, -g.maxSynapseValue, g.maxSynapseValue)
 		if !recurrent {
 			g.forwardSynapses = append(g.forwardSynapses, sid)
 		} else if ep.From == ep.To {
@@ -372,26 +426,40 @@ func (g *NeatGenotype) AddRandomSynapse(counter *Counter, weightStd float64, rec
 	return false
 }
 
+// randomSynapse returns a random synapse. There must be at least one synapse.
+// It picks by index from the synapse lists, which unlike the weights map have a fixed order, so the choice is reproducible without sorting.
+func (g *NeatGenotype) randomSynapse(rng *rand.Rand) NeatSynapseID {
+	i := rng.IntN(len(g.weights))
+	if i < len(g.forwardSynapses) {
+		return g.forwardSynapses[i]
+	}
+	i -= len(g.forwardSynapses)
+	if i < len(g.backwardSynapses) {
+		return g.backwardSynapses[i]
+	}
+	return g.selfSynapses[i-len(g.backwardSynapses)]
+}
+
 // MutateRandomSynapse will change the weight of a random synapse by a random amount from a normal distribution with the given standard deviation.
 // It will return false if there are no synapses to mutate.
-func (g *NeatGenotype) MutateRandomSynapse(std float64) bool {
+func (g *NeatGenotype) MutateRandomSynapse(rng *rand.Rand, std float64) bool {
 	if len(g.weights) == 0 {
 		return false
 	}
 
-	sid := randomMapKey(g.weights)
-	g.weights[sid] = clamp(g.weights[sid]+rand.NormFloat64()*std, -g.maxSynapseValue, g.maxSynapseValue)
+	sid := g.randomSynapse(rng)
+	g.weights[sid] = clamp(g.weights[sid]+rng.NormFloat64()*std, -g.maxSynapseValue, g.maxSynapseValue)
 
 	return true
 }
 
 // RemoveRandomSynapse will remove a random synapse from the genotype.
 // It will return false if there are no synapses to remove.
-func (g *NeatGenotype) RemoveRandomSynapse() bool {
+func (g *NeatGenotype) RemoveRandomSynapse(rng *rand.Rand) bool {
 	if len(g.weights) == 0 {
 		return false
 	}
-	sid := randomMapKey(g.weights)
+	sid := g.randomSynapse(rng)
 	ep := g.synapseEndpointLookup[sid]
 
 	fo, to := g.inverseNeuronOrder[ep.From], g.inverseNeuronOrder[ep.To]
@@ -417,11 +485,11 @@ func (g *NeatGenotype) RemoveRandomSynapse() bool {
 
 // ResetRandomSynapse will reset the weight of a random synapse to 0.
 // It will return false if there are no synapses to reset.
-func (g *NeatGenotype) ResetRandomSynapse() bool {
+func (g *NeatGenotype) ResetRandomSynapse(rng *rand.Rand) bool {
 	if len(g.weights) == 0 {
 		return false
 	}
-	sid := randomMapKey(g.weights)
+	sid := g.randomSynapse(rng)
 	g.weights[sid] = 0
 	return true
 }
@@ -429,13 +497,13 @@ func (g *NeatGenotype) ResetRandomSynapse() bool {
 // MutateRandomActivation will change the activation function of a random hidden neuron to
 // a random activation function from the given list of activations.
 // It will return false if there are no hidden neurons to mutate.
-func (g *NeatGenotype) MutateRandomActivation(activations ...Activation) bool {
+func (g *NeatGenotype) MutateRandomActivation(rng *rand.Rand, activations ...Activation) bool {
 	numHidden := len(g.neuronOrder) - g.numInputs - g.numOutputs
 	if numHidden <= 0 {
 		return false
 	}
-	i := g.numInputs + rand.Intn(numHidden)
-	g.activations[g.neuronOrder[i]] = activations[rand.Intn(len(activations))]
+	i := g.numInputs + rng.IntN(numHidden)
+	g.activations[g.neuronOrder[i]] = activations[rng.IntN(len(activations))]
 	return true
 }
 
@@ -463,14 +531,19 @@ type neatMutationStd struct {
 	// The maximum number of hidden neurons this mutation can add
 	maxHiddenNeurons int
 
-	// The counter to use for new synapse IDs
-	counter *Counter
+	// The random source to use for all mutations
+	rng *rand.Rand
+	// The source of IDs for new neurons and synapses
+	innovations NeatInnovations
 	// The possible activations to use for new neurons
 	possibleActivations []Activation
 }
 
+// NewNeatMutationStd creates a new mutation for [NeatGenotype]s, where the number of each kind of change is drawn from a normal distribution with the given standard deviation.
+// The IDs of new neurons and synapses come from innovations, which may be a [*Counter] or a [NeatInnovationTracker].
 func NewNeatMutationStd(
-	counter *Counter,
+	rng *rand.Rand,
+	innovations NeatInnovations,
 	activations []Activation,
 	stdNumNewForwardSynapses float64,
 	stdNumNewRecurrentSynapses float64,
@@ -482,15 +555,19 @@ func NewNeatMutationStd(
 	stdMutateSynapseWeight float64,
 	maxHiddenNeurons int,
 ) Mutation[*NeatGenotype] {
-	if counter == nil {
-		panic("cannot have nil counter")
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if innovations == nil {
+		panic("cannot have nil innovations")
 	}
 	if len(activations) == 0 {
 		panic("cannot have no activations")
 	}
 	// TODO: Should probably check stds are all above 0 but it wont break anything
 	return &neatMutationStd{
-		counter:                    counter,
+		rng:                        rng,
+		innovations:                innovations,
 		possibleActivations:        activations,
 		stdNumNewSynapses:          stdNumNewForwardSynapses,
 		stdNumNewRecurrentSynapses: stdNumNewRecurrentSynapses,
@@ -506,32 +583,48 @@ func NewNeatMutationStd(
 
 // Reproduce creates a new genotype by crossing over and mutating the given genotypes.
 func (r *neatMutationStd) Mutate(g *NeatGenotype) {
-	for i := 0; i < stdN(r.stdNewSynapseWeight); i++ {
-		g.AddRandomSynapse(r.counter, r.stdNewSynapseWeight, false)
+	for i := 0; i < stdN(r.rng, r.stdNumNewSynapses); i++ {
+		g.AddRandomSynapse(r.rng, r.innovations, r.stdNewSynapseWeight, false)
 	}
-	for i := 0; i < stdN(r.stdNumNewRecurrentSynapses); i++ {
-		g.AddRandomSynapse(r.counter, r.stdNewSynapseWeight, true)
+	for i := 0; i < stdN(r.rng, r.stdNumNewRecurrentSynapses); i++ {
+		g.AddRandomSynapse(r.rng, r.innovations, r.stdNewSynapseWeight, true)
 	}
-	for i := 0; i < stdN(r.stdNumNewNeurons); i++ {
+	for i := 0; i < stdN(r.rng, r.stdNumNewNeurons); i++ {
 		if r.maxHiddenNeurons < 0 || g.NumHiddenNeurons() < r.maxHiddenNeurons {
-			g.AddRandomNeuron(r.counter, r.possibleActivations...)
+			g.AddRandomNeuron(r.rng, r.innovations, r.possibleActivations...)
 		}
 	}
-	for i := 0; i < stdN(r.stdNumMutateSynapses); i++ {
-		g.MutateRandomSynapse(r.stdMutateSynapseWeight)
+	for i := 0; i < stdN(r.rng, r.stdNumMutateSynapses); i++ {
+		g.MutateRandomSynapse(r.rng, r.stdMutateSynapseWeight)
 	}
-	for i := 0; i < stdN(r.stdNumPruneSynapses); i++ {
-		g.RemoveRandomSynapse()
+	for i := 0; i < stdN(r.rng, r.stdNumPruneSynapses); i++ {
+		g.RemoveRandomSynapse(r.rng)
 	}
-	for i := 0; i < stdN(r.stdNumMutateActivations); i++ {
-		g.MutateRandomActivation(r.possibleActivations...)
+	for i := 0; i < stdN(r.rng, r.stdNumMutateActivations); i++ {
+		g.MutateRandomActivation(r.rng, r.possibleActivations...)
 	}
 }
 
-type neatCrossoverSimple struct{}
+// WithScaledStrength implements [Scalable] by scaling the standard deviations of new and mutated synapse weights.
+// The numbers of structural changes, such as how many synapses are added, are not scaled.
+func (r *neatMutationStd) WithScaledStrength(factor float64) any {
+	c := *r
+	c.stdNewSynapseWeight *= factor
+	c.stdMutateSynapseWeight *= factor
+	return &c
+}
+
+type neatCrossoverSimple struct {
+	rng *rand.Rand
+}
 
-func NewNeatCrossoverSimple() Crossover[*NeatGenotype] {
-	return &neatCrossoverSimple{}
+func NewNeatCrossoverSimple(rng *rand.Rand) Crossover[*NeatGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	return &neatCrossoverSimple{
+		rng: rng,
+	}
 }
 
 // Crossover implements CrossoverStrategy.
@@ -555,10 +648,11 @@ func (s *neatCrossoverSimple) Crossover(gs []*NeatGenotype) *NeatGenotype {
 		slices.Clone(g.selfSynapses),
 	}
 
-	for sid, sw := range g2.weights {
+	// Iterate in a fixed order so that the random choices are reproducible
+	for _, sid := range sortedKeys(g2.weights) {
 		if _, ok := gc.weights[sid]; ok {
-			if rand.Float64() > 0.5 {
-				gc.weights[sid] = sw
+			if s.rng.Float64() > 0.5 {
+				gc.weights[sid] = g2.weights[sid]
 			}
 		}
 	}
@@ -571,6 +665,149 @@ func (s *neatCrossoverSimple) NumParents() int {
 	return 2
 }
 
+// neatCrossoverInnovation is a NEAT crossover that lines up the synapses of two parents by their ID.
+// It implements [AgentCrossover], so that it can favour the fitter parent.
+type neatCrossoverInnovation struct {
+	rng *rand.Rand
+}
+
+// NewNeatCrossoverInnovation creates a new crossover that lines up the synapses of two parents by their [NeatSynapseID], as in the NEAT algorithm.
+// Synapses that both parents have get their weight from a random parent.
+// Disjoint and excess synapses, and hidden neurons, are taken from the fitter parent, or from both if they are equally fit.
+// When both parents contribute neurons, the neurons of the second are inserted into the order of the first, next to the neurons they followed in their own order.
+// Any synapse that would change between forward and recurrent in the merged order is left out.
+//
+// Parent fitness is only available when used through [AgentReproduction.ReproduceAgents], which all populations in this package do.
+// If it is called as a plain [Crossover], the parents are treated as equally fit.
+func NewNeatCrossoverInnovation(rng *rand.Rand) AgentCrossover[*NeatGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	return &neatCrossoverInnovation{
+		rng: rng,
+	}
+}
+
+// Crossover implements [Crossover], treating both parents as equally fit.
+func (s *neatCrossoverInnovation) Crossover(gs []*NeatGenotype) *NeatGenotype {
+	if len(gs) != 2 {
+		panic("expected 2 parents for innovation crossover")
+	}
+	return s.crossover(gs[0], gs[1], 0, 0)
+}
+
+// CrossoverAgents implements [AgentCrossover].
+func (s *neatCrossoverInnovation) CrossoverAgents(as []*Agent[*NeatGenotype]) *NeatGenotype {
+	if len(as) != 2 {
+		panic("expected 2 parents for innovation crossover")
+	}
+	return s.crossover(as[0].Genotype, as[1].Genotype, as[0].Fitness, as[1].Fitness)
+}
+
+// NumParents implements [Crossover].
+func (s *neatCrossoverInnovation) NumParents() int {
+	return 2
+}
+
+func (s *neatCrossoverInnovation) crossover(a, b *NeatGenotype, fitnessA, fitnessB float64) *NeatGenotype {
+	if a.numInputs != b.numInputs || a.numOutputs != b.numOutputs {
+		panic("parents must have the same number of inputs and outputs")
+	}
+	// Make sure a is the fitter parent
+	if fitnessB > fitnessA {
+		a, b = b, a
+	}
+	equal := fitnessA == fitnessB
+
+	// Build the neuron order, starting from the fitter parent
+	order := slices.Clone(a.neuronOrder)
+	inverse := maps.Clone(a.inverseNeuronOrder)
+	if equal {
+		// Each new neuron goes directly after the last neuron before it in b's order that is already in the child
+		insertAfter := a.numInputs - 1
+		for _, nid := range b.neuronOrder[b.numInputs : len(b.neuronOrder)-b.numOutputs] {
+			if o, ok := inverse[nid]; ok {
+				insertAfter = o
+				continue
+			}
+			insertAfter = min(max(insertAfter, a.numInputs-1), len(order)-a.numOutputs-1)
+			order = slices.Insert(order, insertAfter+1, nid)
+			insertAfter++
+			for i := insertAfter; i < len(order); i++ {
+				inverse[order[i]] = i
+			}
+		}
+	}
+	activations := make(map[NeatNeuronID]Activation, len(order))
+	for _, nid := range order {
+		actA, inA := a.activations[nid]
+		actB, inB := b.activations[nid]
+		if inA && (!inB || s.rng.Float64() < 0.5) {
+			activations[nid] = actA
+		} else {
+			activations[nid] = actB
+		}
+	}
+	child := &NeatGenotype{
+		maxSynapseValue:       a.maxSynapseValue,
+		numInputs:             a.numInputs,
+		numOutputs:            a.numOutputs,
+		neuronOrder:           order,
+		inverseNeuronOrder:    inverse,
+		activations:           activations,
+		weights:               make(map[NeatSynapseID]float64),
+		synapseEndpointLookup: make(map[NeatSynapseID]NeatSynapseEP),
+		endpointSynapseLookup: make(map[NeatSynapseEP]NeatSynapseID),
+		forwardSynapses:       make([]NeatSynapseID, 0),
+		backwardSynapses:      make([]NeatSynapseID, 0),
+		selfSynapses:          make([]NeatSynapseID, 0),
+	}
+
+	// Line up the synapses by ID. They are visited in a fixed order so that the random choices are reproducible
+	ids := sortedKeys(a.weights)
+	if equal {
+		for id := range b.weights {
+			if _, ok := a.weights[id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+		slices.Sort(ids)
+	}
+	for _, sid := range ids {
+		_, inA := a.weights[sid]
+		_, inB := b.weights[sid]
+		source := a
+		if !inA || (inB && s.rng.Float64() < 0.5) {
+			source = b
+		}
+		ep := source.synapseEndpointLookup[sid]
+		fromOrder, okFrom := inverse[ep.From]
+		toOrder, okTo := inverse[ep.To]
+		if !okFrom || !okTo {
+			continue
+		}
+		if _, ok := child.endpointSynapseLookup[ep]; ok {
+			continue
+		}
+		sourceFrom, sourceTo := source.inverseNeuronOrder[ep.From], source.inverseNeuronOrder[ep.To]
+		if cmp.Compare(fromOrder, toOrder) != cmp.Compare(sourceFrom, sourceTo) {
+			continue
+		}
+		child.weights[sid] = source.weights[sid]
+		child.synapseEndpointLookup[sid] = ep
+		child.endpointSynapseLookup[ep] = sid
+		switch {
+		case fromOrder < toOrder:
+			child.forwardSynapses = append(child.forwardSynapses, sid)
+		case fromOrder > toOrder:
+			child.backwardSynapses = append(child.backwardSynapses, sid)
+		default:
+			child.selfSynapses = append(child.selfSynapses, sid)
+		}
+	}
+	return child
+}
+
 type neatCrossoverAsexual struct{}
 
 func NewNeatCrossoverAsexual() Crossover[*NeatGenotype] {
@@ -619,7 +856,9 @@ func (g *NeatGenotype) Build() Forwarder {
 		fwdWeights[no] = make([]phenotypeConnection, 0)
 		recurrentWeights[no] = make([]phenotypeConnection, 0)
 	}
-	for sid, w := range g.weights {
+	// Add synapses in a fixed order, so that the outputs are always summed in the same order
+	for _, sid := range sortedKeys(g.weights) {
+		w := g.weights[sid]
 		ep := g.synapseEndpointLookup[sid]
 		oa, ob := g.inverseNeuronOrder[ep.From], g.inverseNeuronOrder[ep.To]
 		if ob > oa {
@@ -715,12 +954,12 @@ func (g *NeatGenotype) MarshalJSON() ([]byte, error) {
 		mns[no] = marshallableNeuron{nid, g.activations[nid]}
 	}
 	mss := make([]marshallableSynapse, 0, len(g.weights))
-	for sid, w := range g.weights {
+	for _, sid := range sortedKeys(g.weights) {
 		mss = append(mss, marshallableSynapse{
 			ID:     sid,
 			From:   g.synapseEndpointLookup[sid].From,
 			To:     g.synapseEndpointLookup[sid].To,
-			Weight: w,
+			Weight: g.weights[sid],
 		})
 	}
 	mg := marshallableGenotype{g.numInputs, g.numOutputs, mns, mss, g.maxSynapseValue}
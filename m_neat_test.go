@@ -3,17 +3,20 @@ package goevo
 import (
 	"bytes"
 	"encoding/json"
-	"math/rand"
+	"maps"
+	"slices"
+	"sync"
 	"testing"
 )
 
 func setupNeatTestStuff(numIn, numOut int, useRecurrent bool) Population[*NeatGenotype] {
+	rng := NewRand(0)
 	counter := NewCounter()
 
 	originalGt := NewNeatGenotype(counter, numIn, numOut, Sigmoid)
-	originalGt.AddRandomSynapse(counter, 0.3, false)
+	originalGt.AddRandomSynapse(rng, counter, 0.3, false)
 
-	selec := NewTournamentSelection[*NeatGenotype](3)
+	selec := NewTournamentSelection[*NeatGenotype](rng, 3)
 
 	r := 0.0
 	if useRecurrent {
@@ -21,6 +24,7 @@ func setupNeatTestStuff(numIn, numOut int, useRecurrent bool) Population[*NeatGe
 	}
 
 	mut := NewNeatMutationStd(
+		rng,
 		counter,
 		AllSingleActivations,
 		1,
@@ -33,12 +37,12 @@ func setupNeatTestStuff(numIn, numOut int, useRecurrent bool) Population[*NeatGe
 		0.4,
 		3,
 	)
-	crs := NewNeatCrossoverSimple()
+	crs := NewNeatCrossoverSimple(rng)
 	reprod := NewTwoPhaseReproduction(crs, mut)
 
 	var pop Population[*NeatGenotype] = NewSimplePopulation[*NeatGenotype](func() *NeatGenotype {
 		gt := Clone(originalGt)
-		gt.AddRandomSynapse(counter, 0.3, false)
+		gt.AddRandomSynapse(rng, counter, 0.3, false)
 		return gt
 	}, 100, selec, reprod)
 	return pop
@@ -57,19 +61,20 @@ func TestNeatNewGenotype(t *testing.T) {
 
 // Check we can save and load the genotype
 func TestNeatSaving(t *testing.T) {
+	rng := NewRand(0)
 	counter := NewCounter()
 	gt := NewNeatGenotype(counter, 3, 2, Tanh)
-	gt.AddRandomSynapse(counter, 0.5, false)
-	gt.AddRandomSynapse(counter, 0.5, false)
-	gt.AddRandomSynapse(counter, 0.5, false)
-	gt.AddRandomSynapse(counter, 0.5, false)
-	gt.AddRandomNeuron(counter, Tanh, Relu, Sigmoid)
-	gt.AddRandomNeuron(counter, Tanh, Relu, Sigmoid)
-	gt.AddRandomNeuron(counter, Tanh, Relu, Sigmoid)
-	gt.AddRandomSynapse(counter, 0.5, false)
-	gt.AddRandomSynapse(counter, 0.5, false)
-	gt.AddRandomSynapse(counter, 0.5, false)
-	gt.AddRandomSynapse(counter, 0.5, false)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
+	gt.AddRandomNeuron(rng, counter, Tanh, Relu, Sigmoid)
+	gt.AddRandomNeuron(rng, counter, Tanh, Relu, Sigmoid)
+	gt.AddRandomNeuron(rng, counter, Tanh, Relu, Sigmoid)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
+	gt.AddRandomSynapse(rng, counter, 0.5, false)
 
 	input := []float64{1, 1, 1}
 	originalOutput := gt.Build().Forward(input)
@@ -98,6 +103,7 @@ func TestNeatSaving(t *testing.T) {
 
 // Randomly perform mutation operations on a genotype to check if it remains valid
 func TestNeatGenotypeStressTest(t *testing.T) {
+	rng := NewRand(0)
 	counter := NewCounter()
 	gt := NewNeatGenotype(counter, 5, 3, Sigmoid)
 	if err := gt.Validate(); err != nil {
@@ -110,26 +116,26 @@ func TestNeatGenotypeStressTest(t *testing.T) {
 		if err := cachedGt.Validate(); err != nil {
 			t.Fatalf("error after cloning genotype: %v\nORIGINAL:\n%v\nCLONED:\n%v", err, gt, cachedGt)
 		}
-		opId := rand.Intn(6)
+		opId := rng.IntN(6)
 		switch opId {
 		case 0:
 			op = "AddFwdSynapse"
-			gt.AddRandomSynapse(counter, 0.5, false)
+			gt.AddRandomSynapse(rng, counter, 0.5, false)
 		case 1:
 			op = "AddRecSynapse"
-			gt.AddRandomSynapse(counter, 0.5, true)
+			gt.AddRandomSynapse(rng, counter, 0.5, true)
 		case 2:
 			op = "RemoveSynapse"
-			gt.RemoveRandomSynapse()
+			gt.RemoveRandomSynapse(rng)
 		case 3:
 			op = "AddNeuron"
-			gt.AddRandomNeuron(counter, Relu, Tanh, Sigmoid)
+			gt.AddRandomNeuron(rng, counter, Relu, Tanh, Sigmoid)
 		case 4:
 			op = "MutateSynapse"
-			gt.MutateRandomSynapse(.3)
+			gt.MutateRandomSynapse(rng, .3)
 		case 5:
 			op = "MutateActivation"
-			gt.MutateRandomActivation(Relu, Tanh, Sigmoid)
+			gt.MutateRandomActivation(rng, Relu, Tanh, Sigmoid)
 		}
 		if err := gt.Validate(); err != nil {
 			t.Fatalf("error after performing %v op on genotype: %v\nBEFORE:\n%v\nAFTER:\n%v", err, op, cachedGt, gt)
@@ -144,3 +150,111 @@ func TestNeatXOR(t *testing.T) {
 func TestNeatReccurrent(t *testing.T) {
 	testWithRecurrentDataset(t, setupNeatTestStuff(1, 1, true), nil)
 }
+
+// Check that innovation crossover takes extra synapses from the fitter parent, or both if equally fit, and always makes valid children
+func TestNeatCrossoverInnovation(t *testing.T) {
+	rng := NewRand(0)
+	counter := NewCounter()
+	ancestor := NewNeatGenotype(counter, 3, 2, Sigmoid)
+	for range 4 {
+		ancestor.AddRandomSynapse(rng, counter, 0.5, false)
+	}
+	crs := NewNeatCrossoverInnovation(rng)
+	for range 200 {
+		a, b := Clone(ancestor), Clone(ancestor)
+		for _, g := range []*NeatGenotype{a, b} {
+			for range 5 {
+				g.AddRandomSynapse(rng, counter, 0.5, rng.IntN(3) == 0)
+				g.AddRandomNeuron(rng, counter, Relu, Tanh)
+				g.RemoveRandomSynapse(rng)
+			}
+		}
+		fitter := crs.CrossoverAgents([]*Agent[*NeatGenotype]{{Genotype: a, Fitness: 0}, {Genotype: b, Fitness: 1}})
+		if err := fitter.Validate(); err != nil {
+			t.Fatalf("invalid child with fitter parent: %v", err)
+		}
+		for sid := range fitter.weights {
+			if _, ok := b.weights[sid]; !ok {
+				t.Fatalf("child has synapse %v which the fitter parent does not", sid)
+			}
+		}
+		assertEq(t, fitter.NumNeurons(), b.NumNeurons(), "neurons from fitter parent")
+
+		equal := crs.Crossover([]*NeatGenotype{a, b})
+		if err := equal.Validate(); err != nil {
+			t.Fatalf("invalid child with equal parents: %v", err)
+		}
+		for nid := range b.activations {
+			if _, ok := equal.activations[nid]; !ok {
+				t.Fatalf("child is missing neuron %v from an equally fit parent", nid)
+			}
+		}
+		for sid, ep := range equal.synapseEndpointLookup {
+			if a.synapseEndpointLookup[sid] != ep && b.synapseEndpointLookup[sid] != ep {
+				t.Fatalf("child synapse %v does not match either parent", sid)
+			}
+		}
+	}
+}
+
+// Check that the same structural change in two genotypes gets the same IDs from a tracker, even when made concurrently
+func TestNeatInnovationTracker(t *testing.T) {
+	counter := NewCounter()
+	tracker := NewNeatInnovationTracker(counter)
+	ancestor := NewNeatGenotype(counter, 2, 1, Sigmoid)
+	ancestor.AddRandomSynapse(NewRand(0), tracker, 0.5, false)
+
+	children := make([]*NeatGenotype, 8)
+	wg := &sync.WaitGroup{}
+	for i := range children {
+		children[i] = Clone(ancestor)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every child only has one synapse to split, so they all make the same change
+			children[i].AddRandomNeuron(NewRand(uint64(i)), tracker, Relu)
+		}()
+	}
+	wg.Wait()
+	for _, c := range children {
+		if err := c.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		assertEq(t, slices.Equal(c.neuronOrder, children[0].neuronOrder), true, "shared neuron IDs")
+		assertEq(t, maps.Equal(c.synapseEndpointLookup, children[0].synapseEndpointLookup), true, "shared synapse IDs")
+		assertEq(t, c.CompatibilityDistance(children[0], 1, 1, 1), 0.0, "compatibility distance")
+	}
+
+	// After a reset, the same change gets new IDs
+	tracker.Reset()
+	late := Clone(ancestor)
+	late.AddRandomNeuron(NewRand(0), tracker, Relu)
+	assertEq(t, slices.Equal(late.neuronOrder, children[0].neuronOrder), false, "new neuron ID after reset")
+
+	// A counter never shares IDs
+	a, b := Clone(ancestor), Clone(ancestor)
+	a.AddRandomNeuron(NewRand(0), counter, Relu)
+	b.AddRandomNeuron(NewRand(0), counter, Relu)
+	assertEq(t, slices.Equal(a.neuronOrder, b.neuronOrder), false, "counter neuron IDs")
+}
+
+// Check that scaling a NEAT mutation only changes the weight standard deviations, and returns a copy
+func TestNeatMutationScaledStrength(t *testing.T) {
+	mut := NewNeatMutationStd(NewRand(0), NewCounter(), []Activation{Relu}, 1, 2, 3, 4, 5, 6, 0.5, 0.25, -1).(*neatMutationStd)
+	scaled := mut.WithScaledStrength(2).(*neatMutationStd)
+	assertEq(t, scaled.stdNewSynapseWeight, 1.0, "new synapse weight")
+	assertEq(t, scaled.stdMutateSynapseWeight, 0.5, "mutate synapse weight")
+	assertEq(t, scaled.stdNumNewSynapses, 1.0, "number of new synapses")
+	assertEq(t, mut.stdNewSynapseWeight, 0.5, "original unchanged")
+}
+
+// Check that the number of new forward synapses comes from its own standard deviation, not the new synapse weight one
+func TestNeatMutationNumNewSynapses(t *testing.T) {
+	counter := NewCounter()
+	g := NewNeatGenotype(counter, 2, 1, Linear)
+	mut := NewNeatMutationStd(NewRand(0), counter, []Activation{Relu}, 0, 0, 0, 0, 0, 0, 5, 0, -1)
+	for range 10 {
+		mut.Mutate(g)
+	}
+	assertEq(t, g.NumSynapses(), 0, "num synapses")
+}
@@ -0,0 +1,80 @@
+package goevo
+
+import "sync"
+
+// SynapseID implements [NeatInnovations] by returning a fresh ID.
+func (c *Counter) SynapseID(NeatSynapseEP) NeatSynapseID {
+	return NeatSynapseID(c.Next())
+}
+
+// SplitNeuronID implements [NeatInnovations] by returning a fresh ID.
+func (c *Counter) SplitNeuronID(NeatSynapseID) NeatNeuronID {
+	return NeatNeuronID(c.Next())
+}
+
+// NeatInnovationTracker is a [NeatInnovations] that remembers the structural changes that have been made,
+// so that the same change in different genotypes gets the same ID, as in the NEAT algorithm.
+// A new synapse between the same two neurons always gets the same ID, and splitting the same synapse always creates a neuron with the same ID.
+// This lines up genes from different genotypes, which is needed by [NewNeatCrossoverInnovation] and [NeatGenotype.CompatibilityDistance].
+//
+// By default the tracker remembers changes for the whole run.
+// To only share IDs between changes made in the same generation, as in the original NEAT paper, call [NeatInnovationTracker.Reset] between generations,
+// for example from [Runner.OnGeneration].
+// It is safe to use from multiple goroutines.
+type NeatInnovationTracker struct {
+	lock     *sync.Mutex
+	counter  *Counter
+	synapses map[NeatSynapseEP]NeatSynapseID
+	splits   map[NeatSynapseID]NeatNeuronID
+}
+
+// NewNeatInnovationTracker creates a new NeatInnovationTracker that gets fresh IDs from counter.
+// The counter should be the same one used to create the genotypes with [NewNeatGenotype], so that IDs are never reused.
+func NewNeatInnovationTracker(counter *Counter) *NeatInnovationTracker {
+	if counter == nil {
+		panic("cannot have nil counter")
+	}
+	return &NeatInnovationTracker{
+		lock:     &sync.Mutex{},
+		counter:  counter,
+		synapses: make(map[NeatSynapseEP]NeatSynapseID),
+		splits:   make(map[NeatSynapseID]NeatNeuronID),
+	}
+}
+
+// Next implements [NeatInnovations].
+func (t *NeatInnovationTracker) Next() int {
+	return t.counter.Next()
+}
+
+// SynapseID implements [NeatInnovations].
+func (t *NeatInnovationTracker) SynapseID(ep NeatSynapseEP) NeatSynapseID {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if id, ok := t.synapses[ep]; ok {
+		return id
+	}
+	id := NeatSynapseID(t.counter.Next())
+	t.synapses[ep] = id
+	return id
+}
+
+// SplitNeuronID implements [NeatInnovations].
+func (t *NeatInnovationTracker) SplitNeuronID(split NeatSynapseID) NeatNeuronID {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if id, ok := t.splits[split]; ok {
+		return id
+	}
+	id := NeatNeuronID(t.counter.Next())
+	t.splits[split] = id
+	return id
+}
+
+// Reset forgets every change that has been made, so that later changes get new IDs.
+func (t *NeatInnovationTracker) Reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.synapses = make(map[NeatSynapseEP]NeatSynapseID)
+	t.splits = make(map[NeatSynapseID]NeatNeuronID)
+}
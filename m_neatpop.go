@@ -0,0 +1,258 @@
+package goevo
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+)
+
+// NeatPopulation is a speciated population of [NeatGenotype]s, which groups agents into species by their structure as in the NEAT algorithm.
+//
+// Each new agent joins the first species whose representative is within the compatibility threshold of it (see [NeatGenotype.CompatibilityDistance]),
+// or starts a new species if there is none. The representative of each species is a random member of that species from the previous generation.
+// After each generation the threshold is moved up or down by a small step, to push the number of species towards a target.
+//
+// The number of offspring each species has is proportional to the sum of its adjusted fitnesses, where each member's fitness is divided by the size of its species.
+// This is the same as the mean fitness of the species, so large species do not take over the population.
+// Species whose best fitness has not improved for a number of generations are removed, unless they contain the best agent in the population.
+type NeatPopulation struct {
+	rng             *rand.Rand
+	size            int
+	species         map[int]*neatSpecies
+	nextSpeciesID   int
+	generation      int
+	targetSpecies   int
+	stagnationLimit int
+	// Compatibility parameters, and the current threshold
+	excessCoeff   float64
+	disjointCoeff float64
+	weightCoeff   float64
+	threshold     float64
+	thresholdStep float64
+	selection     Selection[*NeatGenotype]
+	reproduction  Reproduction[*NeatGenotype]
+}
+
+// neatSpecies is a single species in a [NeatPopulation].
+type neatSpecies struct {
+	members        []*Agent[*NeatGenotype]
+	representative *NeatGenotype
+	// bestFitness is the best fitness this species has ever had, and lastImproved the generation it was reached in.
+	bestFitness  float64
+	lastImproved int
+}
+
+// NewNeatPopulation creates a new NeatPopulation with n agents, each with a new genotype created by newGenotype.
+// The compatibility threshold adapts to aim for targetSpecies species, and a species is removed if it has not improved for stagnationLimit generations.
+// Initially, the compatibility distance uses coefficients of 1 for excess and disjoint synapses and 0.4 for weights, and a threshold of 3.
+// These can be changed with [NeatPopulation.WithCompatibility].
+func NewNeatPopulation(
+	rng *rand.Rand,
+	newGenotype func() *NeatGenotype,
+	n int,
+	targetSpecies int,
+	stagnationLimit int,
+	selection Selection[*NeatGenotype],
+	reproduction Reproduction[*NeatGenotype],
+) *NeatPopulation {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if n <= 0 {
+		panic("cannot create population with less than 1 member")
+	}
+	if targetSpecies <= 0 {
+		panic("must have a target of at least 1 species")
+	}
+	if stagnationLimit <= 0 {
+		panic("must have a stagnation limit of at least 1")
+	}
+	if selection == nil {
+		panic("cannot have nil selection")
+	}
+	if reproduction == nil {
+		panic("cannot have nil reproduction")
+	}
+	p := &NeatPopulation{
+		rng:             rng,
+		size:            n,
+		species:         make(map[int]*neatSpecies),
+		targetSpecies:   targetSpecies,
+		stagnationLimit: stagnationLimit,
+		excessCoeff:     1,
+		disjointCoeff:   1,
+		weightCoeff:     0.4,
+		threshold:       3,
+		thresholdStep:   0.3,
+		selection:       selection,
+		reproduction:    reproduction,
+	}
+	for range n {
+		p.speciate(NewAgent(newGenotype()))
+	}
+	return p
+}
+
+// WithCompatibility returns a copy of the population that uses the given compatibility distance coefficients (see [NeatGenotype.CompatibilityDistance]).
+// The threshold starts at threshold, and each generation moves by thresholdStep towards the target number of species.
+// This must be called before the first generation has been created.
+func (p *NeatPopulation) WithCompatibility(excessCoeff, disjointCoeff, weightCoeff, threshold, thresholdStep float64) *NeatPopulation {
+	if threshold <= 0 {
+		panic("must have a threshold greater than 0")
+	}
+	if thresholdStep < 0 {
+		panic("cannot have a negative threshold step")
+	}
+	if p.generation != 0 {
+		panic("can only change compatibility before the first generation")
+	}
+	np := *p
+	np.excessCoeff, np.disjointCoeff, np.weightCoeff = excessCoeff, disjointCoeff, weightCoeff
+	np.threshold, np.thresholdStep = threshold, thresholdStep
+	// The initial agents must be speciated again with the new threshold
+	np.species = make(map[int]*neatSpecies)
+	np.nextSpeciesID = 0
+	for _, a := range p.All() {
+		np.speciate(a)
+	}
+	return &np
+}
+
+// speciate adds the agent to the first compatible species, or a new one.
+func (p *NeatPopulation) speciate(a *Agent[*NeatGenotype]) {
+	for _, id := range sortedKeys(p.species) {
+		s := p.species[id]
+		if a.Genotype.CompatibilityDistance(s.representative, p.excessCoeff, p.disjointCoeff, p.weightCoeff) < p.threshold {
+			s.members = append(s.members, a)
+			return
+		}
+	}
+	p.species[p.nextSpeciesID] = &neatSpecies{
+		members:        []*Agent[*NeatGenotype]{a},
+		representative: a.Genotype,
+		bestFitness:    math.Inf(-1),
+		lastImproved:   p.generation,
+	}
+	p.nextSpeciesID++
+}
+
+// NextGeneration implements [Population].
+func (p *NeatPopulation) NextGeneration() Population[*NeatGenotype] {
+	ids := sortedKeys(p.species)
+	// Find the best agent and lowest fitness, so adjusted fitnesses can be shifted to be positive
+	var best *Agent[*NeatGenotype]
+	minFitness := math.Inf(1)
+	for _, id := range ids {
+		for _, a := range p.species[id].members {
+			if best == nil || a.Fitness > best.Fitness {
+				best = a
+			}
+			minFitness = math.Min(minFitness, a.Fitness)
+		}
+	}
+	// Update the stagnation of each species, and remove stagnant ones
+	next := *p
+	next.generation = p.generation + 1
+	next.species = make(map[int]*neatSpecies)
+	survivors := make([]int, 0, len(ids))
+	shares := make([]float64, 0, len(ids))
+	totalShare := 0.0
+	for _, id := range ids {
+		s := p.species[id]
+		bestFitness, lastImproved := s.bestFitness, s.lastImproved
+		total := 0.0
+		for _, a := range s.members {
+			if a.Fitness > bestFitness {
+				bestFitness, lastImproved = a.Fitness, p.generation
+			}
+			total += a.Fitness - minFitness
+		}
+		if p.generation-lastImproved >= p.stagnationLimit && !slices.Contains(s.members, best) {
+			continue
+		}
+		// The representative is chosen now, as it must come from this generation
+		next.species[id] = &neatSpecies{
+			representative: s.members[p.rng.IntN(len(s.members))].Genotype,
+			bestFitness:    bestFitness,
+			lastImproved:   lastImproved,
+		}
+		survivors = append(survivors, id)
+		// A small constant is added so that species where every agent has the minimum fitness can still reproduce
+		share := total/float64(len(s.members)) + 1e-6
+		shares = append(shares, share)
+		totalShare += share
+	}
+	// Allocate offspring in proportion to the shares, giving any left over from rounding down to the largest remainders
+	numOffspring := make([]int, len(survivors))
+	remainders := make([]float64, len(survivors))
+	allocated := 0
+	for i, share := range shares {
+		exact := share / totalShare * float64(p.size)
+		numOffspring[i] = int(exact)
+		remainders[i] = exact - float64(numOffspring[i])
+		allocated += numOffspring[i]
+	}
+	order := make([]int, len(survivors))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+	for i := 0; allocated < p.size; i++ {
+		numOffspring[order[i%len(order)]]++
+		allocated++
+	}
+	// Breed each species, then place the children into species
+	children := make([]*Agent[*NeatGenotype], 0, p.size)
+	for i, id := range survivors {
+		p.selection.SetAgents(p.species[id].members)
+		for range numOffspring[i] {
+			parents := SelectN(p.selection, p.reproduction.NumParents())
+			children = append(children, NewAgent(ReproduceAgents(p.reproduction, parents)))
+		}
+	}
+	for _, c := range children {
+		next.speciate(c)
+	}
+	for id, s := range next.species {
+		if len(s.members) == 0 {
+			delete(next.species, id)
+		}
+	}
+	// Move the threshold towards the target number of species
+	if len(next.species) > p.targetSpecies {
+		next.threshold += p.thresholdStep
+	} else if len(next.species) < p.targetSpecies {
+		next.threshold = math.Max(p.thresholdStep, next.threshold-p.thresholdStep)
+	}
+	return &next
+}
+
+// All implements [Population].
+// The agents are ordered by the ID of their species.
+func (p *NeatPopulation) All() []*Agent[*NeatGenotype] {
+	all := make([]*Agent[*NeatGenotype], 0, p.size)
+	for _, id := range sortedKeys(p.species) {
+		all = append(all, p.species[id].members...)
+	}
+	return all
+}
+
+// AllSpecies returns the agents of each species, keyed by species ID.
+func (p *NeatPopulation) AllSpecies() map[int][]*Agent[*NeatGenotype] {
+	res := make(map[int][]*Agent[*NeatGenotype])
+	for id, s := range p.species {
+		res[id] = slices.Clone(s.members)
+	}
+	return res
+}
+
+// NumSpecies returns the current number of species.
+func (p *NeatPopulation) NumSpecies() int {
+	return len(p.species)
+}
+
+// Threshold returns the current compatibility threshold.
+func (p *NeatPopulation) Threshold() float64 {
+	return p.threshold
+}
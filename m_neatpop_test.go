@@ -0,0 +1,74 @@
+package goevo
+
+import (
+	"math"
+	"testing"
+)
+
+// Check the compatibility distance counts excess, disjoint, and matching synapses
+func TestNeatCompatibilityDistance(t *testing.T) {
+	rng := NewRand(0)
+	counter := NewCounter()
+	a := NewNeatGenotype(counter, 3, 2, Sigmoid)
+	a.AddRandomSynapse(rng, counter, 0.5, false)
+	a.AddRandomSynapse(rng, counter, 0.5, false)
+	b := Clone(a)
+	assertEq(t, a.CompatibilityDistance(b, 1, 1, 1), 0.0, "identical")
+	// a gets a synapse b does not have, then b gets a later one, so a's is disjoint and b's is excess
+	a.AddRandomSynapse(rng, counter, 0.5, false)
+	b.AddRandomSynapse(rng, counter, 0.5, false)
+	assertEq(t, a.CompatibilityDistance(b, 1, 0, 0), 1.0/3.0, "excess")
+	assertEq(t, a.CompatibilityDistance(b, 0, 1, 0), 1.0/3.0, "disjoint")
+	assertEq(t, a.CompatibilityDistance(b, 0, 0, 1), 0.0, "matching weights")
+}
+
+func setupNeatPopulationTestStuff() *NeatPopulation {
+	rng := NewRand(0)
+	counter := NewCounter()
+	originalGt := NewNeatGenotype(counter, 3, 1, Sigmoid)
+	mut := NewNeatMutationStd(rng, counter, AllSingleActivations, 1, 0, 0.5, 2, 0, 0.5, 0.2, 0.4, 3)
+	reprod := NewTwoPhaseReproduction(NewNeatCrossoverSimple(rng), mut)
+	return NewNeatPopulation(rng, func() *NeatGenotype {
+		gt := Clone(originalGt)
+		gt.AddRandomSynapse(rng, counter, 0.3, false)
+		return gt
+	}, 100, 5, 15, NewTournamentSelection[*NeatGenotype](rng, 3), reprod).WithCompatibility(1, 1, 0.4, 1, 0.1)
+}
+
+// Check that the population size stays fixed and the threshold moves the number of species towards the target
+func TestNeatPopulationSpeciation(t *testing.T) {
+	var pop Population[*NeatGenotype] = setupNeatPopulationTestStuff()
+	numSpecies := 0.0
+	for gen := range 60 {
+		EvaluateFitness(pop.All(), func(g *NeatGenotype) float64 { return -math.Abs(float64(g.NumSynapses() - 6)) }, 4)
+		np := pop.(*NeatPopulation)
+		assertEq(t, len(np.All()), 100, "population size")
+		if gen >= 30 {
+			numSpecies += float64(np.NumSpecies())
+		}
+		pop = pop.NextGeneration()
+	}
+	numSpecies /= 30
+	if numSpecies < 2 || numSpecies > 10 {
+		t.Fatalf("mean number of species %v was not near target", numSpecies)
+	}
+}
+
+func TestNeatPopulationXOR(t *testing.T) {
+	testWithXORDataset(t, Population[*NeatGenotype](setupNeatPopulationTestStuff()), nil)
+}
+
+// Check that the NEAT population also solves XOR with innovation-aligned crossover and tracked innovations
+func TestNeatPopulationXORInnovationCrossover(t *testing.T) {
+	rng := NewRand(1)
+	counter := NewCounter()
+	originalGt := NewNeatGenotype(counter, 3, 1, Sigmoid)
+	mut := NewNeatMutationStd(rng, NewNeatInnovationTracker(counter), AllSingleActivations, 1, 0, 0.5, 2, 0, 0.5, 0.2, 0.4, 3)
+	reprod := NewTwoPhaseReproduction[*NeatGenotype](NewNeatCrossoverInnovation(rng), mut)
+	pop := NewNeatPopulation(rng, func() *NeatGenotype {
+		gt := Clone(originalGt)
+		gt.AddRandomSynapse(rng, counter, 0.3, false)
+		return gt
+	}, 100, 5, 15, NewTournamentSelection[*NeatGenotype](rng, 3), reprod).WithCompatibility(1, 1, 0.4, 1, 0.1)
+	testWithXORDataset(t, Population[*NeatGenotype](pop), nil)
+}
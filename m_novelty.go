@@ -0,0 +1,209 @@
+package goevo
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// NoveltyArchive stores the behaviours of agents that were novel when they were found, for use by a novelty selection.
+// Agents are compared against both the current population and the archive, so the search keeps moving into unexplored behaviours.
+//
+// The archive is only added to by [UpdateNoveltyArchive], which should be called once per generation with the newly evaluated agents,
+// for example by adding [NoveltyArchiveObserver] to a [Runner].
+type NoveltyArchive struct {
+	rng        *rand.Rand
+	threshold  float64
+	chance     float64
+	behaviours [][]float64
+}
+
+// NewNoveltyArchiveThreshold creates a new [NoveltyArchive] that adds every behaviour with a novelty greater than the threshold.
+func NewNoveltyArchiveThreshold(threshold float64) *NoveltyArchive {
+	if threshold < 0 {
+		panic("cannot have threshold < 0")
+	}
+	return &NoveltyArchive{
+		threshold: threshold,
+		chance:    -1,
+	}
+}
+
+// NewNoveltyArchiveRandom creates a new [NoveltyArchive] that adds each behaviour with the given chance, regardless of its novelty.
+func NewNoveltyArchiveRandom(rng *rand.Rand, chance float64) *NoveltyArchive {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if chance < 0 || chance > 1 {
+		panic("cannot have chance out of range 0-1")
+	}
+	return &NoveltyArchive{
+		rng:    rng,
+		chance: chance,
+	}
+}
+
+// consider adds the behaviour to the archive if the archive's policy accepts it.
+func (a *NoveltyArchive) consider(behaviour []float64, novelty float64) {
+	var add bool
+	if a.chance < 0 {
+		add = novelty > a.threshold
+	} else {
+		add = a.rng.Float64() < a.chance
+	}
+	if add {
+		a.behaviours = append(a.behaviours, slices.Clone(behaviour))
+	}
+}
+
+// Len returns the number of behaviours in the archive.
+func (a *NoveltyArchive) Len() int {
+	return len(a.behaviours)
+}
+
+// Behaviours returns a copy of every behaviour in the archive, in the order they were added.
+func (a *NoveltyArchive) Behaviours() [][]float64 {
+	bs := make([][]float64, len(a.behaviours))
+	for i := range bs {
+		bs[i] = slices.Clone(a.behaviours[i])
+	}
+	return bs
+}
+
+// UpdateNoveltyArchive considers adding the behaviour of each candidate to the archive.
+// The novelty of each candidate is the mean distance to its k nearest behaviours in the population (excluding itself) and the archive.
+// Every candidate is scored before any are added, so the order of the candidates does not matter.
+// It should be called once per generation, with the agents evaluated in that generation as the candidates,
+// so that each behaviour is only considered once.
+func UpdateNoveltyArchive[T any](archive *NoveltyArchive, candidates, population []*Agent[T], k int) {
+	if archive == nil {
+		panic("cannot have nil archive")
+	}
+	if k <= 0 {
+		panic("must have k of at least 1")
+	}
+	novelties := noveltiesOf(candidates, population, archive, k)
+	for i, a := range candidates {
+		archive.consider(a.Behaviour, novelties[i])
+	}
+}
+
+// NoveltyArchiveObserver returns a function for [Runner.OnGeneration] that calls [UpdateNoveltyArchive] after each generation has been evaluated.
+// The candidates are the agents that were evaluated in that generation (see [IncrementalPopulation]), and their novelty is measured against the whole population.
+func NoveltyArchiveObserver[T any](archive *NoveltyArchive, k int) func(RunSummary, Population[T]) error {
+	if archive == nil {
+		panic("cannot have nil archive")
+	}
+	if k <= 0 {
+		panic("must have k of at least 1")
+	}
+	return func(_ RunSummary, pop Population[T]) error {
+		candidates := pop.All()
+		if ip, ok := pop.(IncrementalPopulation[T]); ok {
+			candidates = ip.Unevaluated()
+		}
+		UpdateNoveltyArchive(archive, candidates, pop.All(), k)
+		return nil
+	}
+}
+
+// noveltiesOf returns the novelty of each of the agents, which is the mean distance from its behaviour
+// to the k nearest behaviours in the population (excluding itself) and the archive.
+func noveltiesOf[T any](agents, population []*Agent[T], archive *NoveltyArchive, k int) []float64 {
+	novelties := make([]float64, len(agents))
+	distances := make([]float64, 0, len(population)+len(archive.behaviours))
+	for i, a := range agents {
+		if len(a.Behaviour) == 0 {
+			panic("all agents must have a behaviour set for novelty")
+		}
+		distances = distances[:0]
+		for _, b := range population {
+			if b != a {
+				distances = append(distances, behaviourDistance(a.Behaviour, b.Behaviour))
+			}
+		}
+		for _, b := range archive.behaviours {
+			distances = append(distances, behaviourDistance(a.Behaviour, b))
+		}
+		novelties[i] = meanOfSmallest(distances, k)
+	}
+	return novelties
+}
+
+// noveltySelection is a [Selection] that selects agents by a blend of their novelty and fitness, using another selection strategy.
+// The novelty of an agent is the mean distance from its behaviour to the k nearest behaviours in the population and the archive.
+type noveltySelection[T any] struct {
+	rescored      *rescoredSelection[T]
+	archive       *NoveltyArchive
+	k             int
+	noveltyWeight float64
+}
+
+// NewNoveltySelection creates a new [Selection] for novelty search.
+// Each generation, every agent is scored with (1-noveltyWeight)*fitness + noveltyWeight*novelty, and the inner selection then selects using that score.
+// A noveltyWeight of 1 is pure novelty search.
+// Every agent must have its [Agent.Behaviour] set before [Selection.SetAgents] is called.
+// Novelty is measured against the agents given to [Selection.SetAgents] and the archive, so populations that select from subsets,
+// such as a species or a neighbourhood, measure novelty within that subset.
+// The selection only reads the archive. Use [UpdateNoveltyArchive] or [NoveltyArchiveObserver] to add to it once per generation.
+// The agents themselves are never modified, so their fitness is still available for reporting.
+func NewNoveltySelection[T any](inner Selection[T], archive *NoveltyArchive, k int, noveltyWeight float64) Selection[T] {
+	if archive == nil {
+		panic("cannot have nil archive")
+	}
+	if k <= 0 {
+		panic("must have k of at least 1")
+	}
+	if noveltyWeight < 0 || noveltyWeight > 1 {
+		panic("cannot have novelty weight out of range 0-1")
+	}
+	return &noveltySelection[T]{
+		rescored:      newRescoredSelection(inner),
+		archive:       archive,
+		k:             k,
+		noveltyWeight: noveltyWeight,
+	}
+}
+
+// SetAgents implements [Selection].
+// It calculates the novelty of each agent, but does not update the archive.
+func (s *noveltySelection[T]) SetAgents(agents []*Agent[T]) {
+	novelties := noveltiesOf(agents, agents, s.archive, s.k)
+	scores := make([]float64, len(agents))
+	for i, a := range agents {
+		scores[i] = (1-s.noveltyWeight)*a.Fitness + s.noveltyWeight*novelties[i]
+	}
+	s.rescored.setAgents(agents, scores)
+}
+
+// Select implements [Selection].
+func (s *noveltySelection[T]) Select() *Agent[T] {
+	return s.rescored.selectAgent()
+}
+
+// behaviourDistance returns the euclidean distance between two behaviours.
+func behaviourDistance(a, b []float64) float64 {
+	if len(a) != len(b) {
+		panic("behaviours must have the same length")
+	}
+	total := 0.0
+	for i := range a {
+		total += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(total)
+}
+
+// meanOfSmallest returns the mean of the k smallest values, or of all values if there are fewer than k.
+// It sorts xs in place.
+func meanOfSmallest(xs []float64, k int) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	slices.Sort(xs)
+	k = min(k, len(xs))
+	total := 0.0
+	for _, x := range xs[:k] {
+		total += x
+	}
+	return total / float64(k)
+}
@@ -0,0 +1,85 @@
+package goevo
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// Check novelty is calculated from the nearest neighbours, and that the archive only takes novel behaviours when it is updated
+func TestNoveltySelectionScores(t *testing.T) {
+	archive := NewNoveltyArchiveThreshold(5)
+	selec := NewNoveltySelection(NewEliteSelection[int](), archive, 1, 1)
+	agents := []*Agent[int]{
+		{Genotype: 0, Fitness: 3, Behaviour: []float64{0}},
+		{Genotype: 1, Fitness: 2, Behaviour: []float64{1}},
+		{Genotype: 2, Fitness: 1, Behaviour: []float64{10}},
+	}
+	selec.SetAgents(agents)
+	selected := selec.Select()
+	if selected != agents[2] {
+		t.Fatalf("expected the most novel agent to be selected, got %v", selected)
+	}
+	assertEq(t, selected.Fitness, 1.0, "fitness is unchanged")
+	assertEq(t, archive.Len(), 0, "selection does not update the archive")
+	UpdateNoveltyArchive(archive, agents, agents, 1)
+	assertEq(t, archive.Len(), 1, "archive length")
+	assertEq(t, archive.Behaviours()[0][0], 10.0, "archived behaviour")
+
+	// Now the archive contains 10, so the agent at 10 is no longer novel
+	selec.SetAgents(agents)
+	if selec.Select() == agents[2] {
+		t.Fatal("expected the archive to reduce the novelty of the archived behaviour")
+	}
+}
+
+// Check that novelty search can drive a neat population to explore its output space
+func TestNoveltySelectionNeat(t *testing.T) {
+	rng := NewRand(0)
+	counter := NewCounter()
+	archive := NewNoveltyArchiveRandom(rng, 0.05)
+	selec := NewNoveltySelection(NewTournamentSelection[*NeatGenotype](rng, 3), archive, 10, 1)
+	mut := NewNeatMutationStd(rng, counter, AllSingleActivations, 1, 0, 0.5, 2, 0, 0.5, 0.2, 0.4, 3)
+	reprod := NewTwoPhaseReproduction(NewNeatCrossoverSimple(rng), mut)
+	pop := NewSimplePopulation(func() *NeatGenotype {
+		return NewNeatGenotype(counter, 2, 2, Tanh)
+	}, 50, selec, reprod)
+
+	// The behaviour is the output of the network, and there is no fitness
+	evaluate := func(_ context.Context, a *Agent[*NeatGenotype]) {
+		a.Behaviour = a.Genotype.Build().Forward([]float64{1, 1})
+	}
+	runner := NewAgentRunner(evaluate, 4, NewStopMaxGenerations(50))
+	runner.OnGeneration(NoveltyArchiveObserver[*NeatGenotype](archive, 10))
+	_, final, _, _ := runner.RunContext(context.Background(), pop)
+
+	if archive.Len() == 0 {
+		t.Fatal("expected some behaviours to be archived")
+	}
+	spread := 0.0
+	for _, a := range final.All() {
+		spread = math.Max(spread, behaviourDistance(a.Behaviour, []float64{0, 0}))
+	}
+	if spread < 0.5 {
+		t.Fatalf("expected novelty search to find outputs away from the origin, furthest was %v", spread)
+	}
+}
+
+// Check that each evaluated agent is only considered for the archive once, even when the population selects many times per generation
+func TestNoveltyArchiveSteadyState(t *testing.T) {
+	rng := NewRand(0)
+	archive := NewNoveltyArchiveRandom(rng, 1)
+	selec := NewNoveltySelection(NewTournamentSelection[*ArrayGenotype[float64]](rng, 3), archive, 3, 1)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0.0, 0.1), 1))
+	pop := NewSteadyStatePopulation(func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(2, NewGeneratorNormal(rng, 0.0, 1.0))
+	}, 10, 2, selec, reprod, NewReplaceOldest[*ArrayGenotype[float64]]())
+	evaluate := func(_ context.Context, a *Agent[*ArrayGenotype[float64]]) {
+		a.Behaviour = []float64{a.Genotype.At(0), a.Genotype.At(1)}
+	}
+	runner := NewAgentRunner(evaluate, 1, NewStopMaxGenerations(20))
+	runner.OnGeneration(NoveltyArchiveObserver[*ArrayGenotype[float64]](archive, 3))
+	_, _, summary, _ := runner.RunContext(context.Background(), pop)
+	// The archive accepts every behaviour it considers, so it should have exactly one per evaluation
+	assertEq(t, archive.Len(), summary.Evaluations, "archive length")
+}
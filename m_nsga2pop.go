@@ -0,0 +1,210 @@
+package goevo
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// NSGA2Population is a multi-objective population that uses the NSGA-II algorithm.
+// Each agent must have its [Agent.Objectives] set before the next generation is created, and every objective is maximised.
+//
+// The population contains both the surviving parents of the last generation and their offspring.
+// Each generation, the agents are sorted into non-dominated fronts, and the best half survive, using crowding distance to break ties in the last front.
+// The survivors then produce the same number of offspring, with parents chosen by binary crowded tournament selection.
+// The survivors keep their objectives, so only the offspring need evaluating, and the population is an [IncrementalPopulation].
+type NSGA2Population[T any] struct {
+	rng    *rand.Rand
+	size   int
+	agents []*Agent[T]
+	// numSurvivors is the number of agents at the start of agents that survived from the last generation.
+	numSurvivors int
+	reproduction Reproduction[T]
+}
+
+// NewNSGA2Population creates a new NSGA2Population which keeps n survivors each generation.
+// The first generation has n agents, each with a new genotype created by newGenotype, and later generations have 2n.
+func NewNSGA2Population[T any](rng *rand.Rand, newGenotype func() T, n int, reproduction Reproduction[T]) *NSGA2Population[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if reproduction == nil {
+		panic("cannot have nil reproduction")
+	}
+	if n <= 0 {
+		panic("cannot create population with less than 1 member")
+	}
+	agents := make([]*Agent[T], n)
+	for i := range agents {
+		agents[i] = NewAgent(newGenotype())
+	}
+	return &NSGA2Population[T]{
+		rng:          rng,
+		size:         n,
+		agents:       agents,
+		reproduction: reproduction,
+	}
+}
+
+// NextGeneration implements [Population].
+func (p *NSGA2Population[T]) NextGeneration() Population[T] {
+	fronts := nonDominatedSort(p.agents)
+	// Fill the survivors front by front, and store the rank and crowding distance of each for the tournament
+	survivors := make([]*Agent[T], 0, p.size)
+	ranks := make([]int, 0, p.size)
+	crowding := make([]float64, 0, p.size)
+	for rank, front := range fronts {
+		if len(survivors) >= p.size {
+			break
+		}
+		distances := crowdingDistances(front)
+		if len(survivors)+len(front) > p.size {
+			// Only some of this front fits, so take the least crowded
+			order := make([]int, len(front))
+			for i := range order {
+				order[i] = i
+			}
+			sort.SliceStable(order, func(i, j int) bool { return distances[order[i]] > distances[order[j]] })
+			for _, i := range order[:p.size-len(survivors)] {
+				survivors = append(survivors, front[i])
+				ranks = append(ranks, rank)
+				crowding = append(crowding, distances[i])
+			}
+			break
+		}
+		survivors = append(survivors, front...)
+		for i := range front {
+			ranks = append(ranks, rank)
+			crowding = append(crowding, distances[i])
+		}
+	}
+	// Create the offspring
+	tournament := func() *Agent[T] {
+		a, b := p.rng.IntN(len(survivors)), p.rng.IntN(len(survivors))
+		if ranks[b] < ranks[a] || (ranks[b] == ranks[a] && crowding[b] > crowding[a]) {
+			a = b
+		}
+		return survivors[a]
+	}
+	agents := make([]*Agent[T], 0, 2*p.size)
+	agents = append(agents, survivors...)
+	for range p.size {
+		parents := make([]*Agent[T], p.reproduction.NumParents())
+		for i := range parents {
+			parents[i] = tournament()
+		}
+		agents = append(agents, NewAgent(ReproduceAgents(p.reproduction, parents)))
+	}
+	return &NSGA2Population[T]{
+		rng:          p.rng,
+		size:         p.size,
+		agents:       agents,
+		numSurvivors: len(survivors),
+		reproduction: p.reproduction,
+	}
+}
+
+// All implements [Population].
+// The surviving parents come first, followed by their offspring.
+func (p *NSGA2Population[T]) All() []*Agent[T] {
+	return p.agents
+}
+
+// Unevaluated implements [IncrementalPopulation].
+// It returns the offspring, or every agent in the first generation.
+func (p *NSGA2Population[T]) Unevaluated() []*Agent[T] {
+	return p.agents[p.numSurvivors:]
+}
+
+// ParetoFront returns the agents that are not dominated by any other agent in the population, or nil if there are no agents.
+// The objectives of every agent must have been set.
+func (p *NSGA2Population[T]) ParetoFront() []*Agent[T] {
+	if len(p.agents) == 0 {
+		return nil
+	}
+	return nonDominatedSort(p.agents)[0]
+}
+
+// dominates returns true if a is at least as good as b in every objective, and better in at least one.
+func dominates(a, b []float64) bool {
+	if len(a) != len(b) {
+		panic("agents must have the same number of objectives")
+	}
+	better := false
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// nonDominatedSort sorts the agents into fronts using the fast non-dominated sort from NSGA-II.
+// The first front contains the agents that are not dominated by any other, the second those only dominated by the first, and so on.
+// Agents keep their relative order within each front.
+func nonDominatedSort[T any](agents []*Agent[T]) [][]*Agent[T] {
+	for _, a := range agents {
+		if len(a.Objectives) == 0 {
+			panic("all agents must have objectives set")
+		}
+	}
+	dominatedBy := make([]int, len(agents))
+	dominating := make([][]int, len(agents))
+	current := make([]int, 0)
+	for i := range agents {
+		for j := range agents {
+			if dominates(agents[i].Objectives, agents[j].Objectives) {
+				dominating[i] = append(dominating[i], j)
+			} else if dominates(agents[j].Objectives, agents[i].Objectives) {
+				dominatedBy[i]++
+			}
+		}
+		if dominatedBy[i] == 0 {
+			current = append(current, i)
+		}
+	}
+	fronts := make([][]*Agent[T], 0)
+	for len(current) > 0 {
+		front := make([]*Agent[T], len(current))
+		next := make([]int, 0)
+		for fi, i := range current {
+			front[fi] = agents[i]
+			for _, j := range dominating[i] {
+				dominatedBy[j]--
+				if dominatedBy[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		sort.Ints(next)
+		fronts = append(fronts, front)
+		current = next
+	}
+	return fronts
+}
+
+// crowdingDistances returns the crowding distance of each agent in the front.
+// Agents at the edge of the front in any objective have infinite distance.
+func crowdingDistances[T any](front []*Agent[T]) []float64 {
+	distances := make([]float64, len(front))
+	order := make([]int, len(front))
+	for m := range front[0].Objectives {
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return front[order[i]].Objectives[m] < front[order[j]].Objectives[m] })
+		lo, hi := front[order[0]].Objectives[m], front[order[len(order)-1]].Objectives[m]
+		distances[order[0]] = math.Inf(1)
+		distances[order[len(order)-1]] = math.Inf(1)
+		if hi == lo {
+			continue
+		}
+		for k := 1; k < len(order)-1; k++ {
+			distances[order[k]] += (front[order[k+1]].Objectives[m] - front[order[k-1]].Objectives[m]) / (hi - lo)
+		}
+	}
+	return distances
+}
@@ -0,0 +1,58 @@
+package goevo
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// Check the fronts are found correctly for a known set of objectives
+func TestNonDominatedSort(t *testing.T) {
+	objectives := [][]float64{{1, 1}, {3, 1}, {1, 3}, {2, 2}, {0, 0}, {1, 2}}
+	agents := make([]*Agent[int], len(objectives))
+	for i := range agents {
+		agents[i] = &Agent[int]{Genotype: i, Objectives: objectives[i]}
+	}
+	fronts := nonDominatedSort(agents)
+	expected := [][]int{{1, 2, 3}, {5}, {0}, {4}}
+	assertEq(t, len(fronts), len(expected), "num fronts")
+	for fi := range fronts {
+		assertEq(t, len(fronts[fi]), len(expected[fi]), "front length")
+		for i := range fronts[fi] {
+			assertEq(t, fronts[fi][i].Genotype, expected[fi][i], "front member")
+		}
+	}
+}
+
+// Check the population spreads out along the pareto front of Schaffer's function
+func TestNSGA2Schaffer(t *testing.T) {
+	rng := NewRand(0)
+	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0, 0.1), 1)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverAsexual[float64](), mut)
+	pop := NewNSGA2Population(rng, func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(1, NewGeneratorNormal(rng, 0.0, 10.0))
+	}, 50, reprod)
+
+	evaluate := func(_ context.Context, a *Agent[*ArrayGenotype[float64]]) {
+		x := a.Genotype.At(0)
+		a.Objectives = []float64{-x * x, -(x - 2) * (x - 2)}
+		a.Fitness = a.Objectives[0] + a.Objectives[1]
+	}
+	runner := NewAgentRunner(evaluate, 4, NewStopMaxGenerations(100))
+	_, final, summary, _ := runner.RunContext(context.Background(), pop)
+	assertEq(t, summary.Evaluations, 50*summary.Generations, "only offspring are evaluated")
+	assertEq(t, len((&NSGA2Population[int]{}).ParetoFront()), 0, "empty pareto front")
+
+	front := final.(*NSGA2Population[*ArrayGenotype[float64]]).ParetoFront()
+	lo, hi := 100.0, -100.0
+	for _, a := range front {
+		x := a.Genotype.At(0)
+		if x < -0.1 || x > 2.1 {
+			t.Fatalf("agent with x=%v is not on the pareto front", x)
+		}
+		lo, hi = math.Min(lo, x), math.Max(hi, x)
+	}
+	if lo > 0.2 || hi < 1.8 {
+		t.Fatalf("pareto front is not spread out, covers %v to %v", lo, hi)
+	}
+}
@@ -0,0 +1,94 @@
+package goevo
+
+import "math/rand/v2"
+
+// replaceWorst is a [Replacement] that always replaces the least fit member.
+type replaceWorst[T any] struct{}
+
+// NewReplaceWorst creates a new [Replacement] that always replaces the least fit member.
+// If several members are equally bad, the oldest of them is replaced.
+func NewReplaceWorst[T any]() Replacement[T] {
+	return &replaceWorst[T]{}
+}
+
+// Replace implements [Replacement].
+func (r *replaceWorst[T]) Replace(members []*Agent[T], child *Agent[T]) int {
+	worst := 0
+	for i, m := range members {
+		if m.Fitness < members[worst].Fitness {
+			worst = i
+		}
+	}
+	return worst
+}
+
+// replaceOldest is a [Replacement] that always replaces the oldest member.
+type replaceOldest[T any] struct{}
+
+// NewReplaceOldest creates a new [Replacement] that always replaces the oldest member, regardless of fitness.
+func NewReplaceOldest[T any]() Replacement[T] {
+	return &replaceOldest[T]{}
+}
+
+// Replace implements [Replacement].
+func (r *replaceOldest[T]) Replace(members []*Agent[T], child *Agent[T]) int {
+	return 0
+}
+
+// replaceTournamentLoser is a [Replacement] that replaces the least fit member of a random tournament.
+type replaceTournamentLoser[T any] struct {
+	rng            *rand.Rand
+	tournamentSize int
+}
+
+// NewReplaceTournamentLoser creates a new [Replacement] that picks tournamentSize random members and replaces the least fit of them.
+func NewReplaceTournamentLoser[T any](rng *rand.Rand, tournamentSize int) Replacement[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if tournamentSize <= 0 {
+		panic("must have at least tournament size of 1")
+	}
+	return &replaceTournamentLoser[T]{
+		rng:            rng,
+		tournamentSize: tournamentSize,
+	}
+}
+
+// Replace implements [Replacement].
+func (r *replaceTournamentLoser[T]) Replace(members []*Agent[T], child *Agent[T]) int {
+	loser := r.rng.IntN(len(members))
+	for range r.tournamentSize - 1 {
+		i := r.rng.IntN(len(members))
+		if members[i].Fitness < members[loser].Fitness {
+			loser = i
+		}
+	}
+	return loser
+}
+
+// replaceIfBetter is a [Replacement] that only lets another replacement go ahead if the child is fitter than the member it would replace.
+type replaceIfBetter[T any] struct {
+	replacement Replacement[T]
+}
+
+// NewReplaceIfBetter creates a new [Replacement] that chooses a member with the given replacement,
+// but discards the child instead if it is not fitter than that member.
+// For example, NewReplaceIfBetter(NewReplaceWorst[T]()) only accepts children that are better than the worst member.
+func NewReplaceIfBetter[T any](replacement Replacement[T]) Replacement[T] {
+	if replacement == nil {
+		panic("cannot have nil replacement")
+	}
+	return &replaceIfBetter[T]{
+		replacement: replacement,
+	}
+}
+
+// Replace implements [Replacement].
+func (r *replaceIfBetter[T]) Replace(members []*Agent[T], child *Agent[T]) int {
+	i := r.replacement.Replace(members, child)
+	if i < 0 || child.Fitness <= members[i].Fitness {
+		return -1
+	}
+	return i
+}
@@ -1,46 +1,95 @@
 package goevo
 
+import "slices"
+
 // SimplePopulation has a single species, and generates the entire next generation by selcting and breeding from the previous one.
+// Optionally, the fittest agents can be copied unchanged into the next generation, using [SimplePopulation.WithElitism].
 type SimplePopulation[T any] struct {
 	agents       []*Agent[T]
 	selection    Selection[T]
 	reproduction Reproduction[T]
+	// numElites is the number of fittest agents copied into the next generation.
+	numElites int
+	// reevaluateElites is whether elites are evaluated again in the generation they are copied into.
+	reevaluateElites bool
+	// numCarried is the number of agents at the start of agents that were copied from the previous generation as elites.
+	numCarried int
 }
 
 // NewSimplePopulation creates a new SimplePopulation with n agents, each with a new genotype created by newGenotype.
 func NewSimplePopulation[T any](newGenotype func() T, n int, selection Selection[T], reproduction Reproduction[T]) *SimplePopulation[T] {
+	if n <= 0 {
+		panic("cannot create population with less than 1 member")
+	}
+	agents := make([]*Agent[T], n)
+	for i := range agents {
+		agents[i] = NewAgent(newGenotype())
+	}
+	return NewSimplePopulationFrom(agents, selection, reproduction)
+}
+
+// NewSimplePopulationFrom creates a new SimplePopulation containing the given agents.
+// The agents keep their fitness, so this can be used to continue a population from a [Checkpoint].
+func NewSimplePopulationFrom[T any](agents []*Agent[T], selection Selection[T], reproduction Reproduction[T]) *SimplePopulation[T] {
 	if selection == nil {
 		panic("cannot have nil selection")
 	}
 	if reproduction == nil {
 		panic("cannot have nil reproduction")
 	}
-	if n <= 0 {
+	if len(agents) == 0 {
 		panic("cannot create population with less than 1 member")
 	}
-	agents := make([]*Agent[T], n)
-	for i := range agents {
-		agents[i] = NewAgent(newGenotype())
-	}
 	return &SimplePopulation[T]{
-		agents:       agents,
+		agents:       slices.Clone(agents),
 		selection:    selection,
 		reproduction: reproduction,
 	}
 }
 
+// WithElitism returns a copy of the population where, each generation, the numElites fittest agents are copied unchanged into the next generation.
+// Elites keep their fitness and are not evaluated again, unless reevaluate is true (for example if the fitness function is noisy).
+func (p *SimplePopulation[T]) WithElitism(numElites int, reevaluate bool) *SimplePopulation[T] {
+	if numElites < 0 || numElites >= len(p.agents) {
+		panic("number of elites must be at least 0 and less than the population size")
+	}
+	np := *p
+	np.numElites = numElites
+	np.reevaluateElites = reevaluate
+	return &np
+}
+
 // NextGeneration creates a new SimplePopulation from the current one, using the given selection and reproduction strategies.
 func (p *SimplePopulation[T]) NextGeneration() Population[T] {
 	p.selection.SetAgents(p.agents)
-	return NewSimplePopulation(func() T {
-		parents := SelectNGenotypes(p.selection, p.reproduction.NumParents())
-		return p.reproduction.Reproduce(parents)
-	}, len(p.agents), p.selection, p.reproduction)
+	agents := copyElites(p.agents, p.numElites)
+	for len(agents) < len(p.agents) {
+		parents := SelectN(p.selection, p.reproduction.NumParents())
+		agents = append(agents, NewAgent(ReproduceAgents(p.reproduction, parents)))
+	}
+	return &SimplePopulation[T]{
+		agents:           agents,
+		selection:        p.selection,
+		reproduction:     p.reproduction,
+		numElites:        p.numElites,
+		reevaluateElites: p.reevaluateElites,
+		numCarried:       p.numElites,
+	}
 }
 
 // Agents returns the agents in the population.
+// If elitism is enabled, the elites come first.
 //
 // TODO(change this to an iterator once they get added to the language, as this will increase performance in other cases)
 func (p *SimplePopulation[T]) All() []*Agent[T] {
 	return p.agents
 }
+
+// Unevaluated implements [IncrementalPopulation].
+// It returns every agent except the elites copied from the last generation, unless they should be re-evaluated.
+func (p *SimplePopulation[T]) Unevaluated() []*Agent[T] {
+	if p.reevaluateElites {
+		return p.agents
+	}
+	return p.agents[p.numCarried:]
+}
@@ -3,13 +3,14 @@ package goevo
 import (
 	"fmt"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"slices"
 )
 
 // SpeciatedPopulation is a speciated population of agents.
 // Each species has the same number of agents, and there are always the same number of species.
 // Each generation, with a chance, the worst species is removed, and replaced with a random species or the best species.
+// Optionally, the fittest agents of each species can be copied unchanged into the next generation, using [SpeciatedPopulation.WithElitism].
 type SpeciatedPopulation[T any] struct {
 	// species is a map of species ID to a slice of agents.
 	species map[int][]*Agent[T]
@@ -18,16 +19,25 @@ type SpeciatedPopulation[T any] struct {
 	// stdNumAgentsSwap is the standard deviation of the number of agents to swap between species each generation.
 	// An agent is swapped by moving it to another random species, and moving another agent from that species to this species.
 	stdNumAgentsSwap float64
+	// rng is the random source used for removing and swapping agents.
+	rng *rand.Rand
 	// counter is a counter to keep track of the new species.
 	counter *Counter
 	// The selection strategy to use when selecting agents to reproduce.
 	selection Selection[T]
 	// The reproduction strategy to use when creating new agents.
 	reproduction Reproduction[T]
+	// numElites is the number of fittest agents of each species copied into the next generation.
+	numElites int
+	// reevaluateElites is whether elites are evaluated again in the generation they are copied into.
+	reevaluateElites bool
+	// numCarried is the number of agents at the start of each species that were copied from the previous generation as elites.
+	numCarried int
 }
 
 // NewSpeciatedPopulation creates a new speciated population.
 func NewSpeciatedPopulation[T any](
+	rng *rand.Rand,
 	counter *Counter,
 	newGenotype func() T,
 	numSpecies int,
@@ -45,10 +55,11 @@ func NewSpeciatedPopulation[T any](
 		}
 		species[counter.Next()] = agents
 	}
-	return NewSpeciatedPopulationFrom(counter, species, removeWorstSpeciesChance, stdNumAgentsSwap, selection, reproduction)
+	return NewSpeciatedPopulationFrom(rng, counter, species, removeWorstSpeciesChance, stdNumAgentsSwap, selection, reproduction)
 }
 
 func NewSpeciatedPopulationFrom[T any](
+	rng *rand.Rand,
 	counter *Counter,
 	species map[int][]*Agent[T],
 	removeWorstSpeciesChance float64,
@@ -56,6 +67,9 @@ func NewSpeciatedPopulationFrom[T any](
 	selection Selection[T],
 	reproduction Reproduction[T],
 ) *SpeciatedPopulation[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
 	if len(species) == 0 {
 		panic("can only create speciated population with at least one species")
 	}
@@ -85,12 +99,28 @@ func NewSpeciatedPopulationFrom[T any](
 		species:                  speciesCopy,
 		removeWorstSpeciesChance: removeWorstSpeciesChance,
 		stdNumAgentsSwap:         stdNumAgentsSwap,
+		rng:                      rng,
 		counter:                  counter,
 		selection:                selection,
 		reproduction:             reproduction,
 	}
 }
 
+// WithElitism returns a copy of the population where, each generation, the numElites fittest agents of each species are copied unchanged into the next generation.
+// Elites stay in their species, and are never swapped into another.
+// They keep their fitness and are not evaluated again, unless reevaluate is true (for example if the fitness function is noisy).
+func (p *SpeciatedPopulation[T]) WithElitism(numElites int, reevaluate bool) *SpeciatedPopulation[T] {
+	for _, agents := range p.species {
+		if numElites < 0 || numElites >= len(agents) {
+			panic("number of elites must be at least 0 and less than the species size")
+		}
+	}
+	np := *p
+	np.numElites = numElites
+	np.reevaluateElites = reevaluate
+	return &np
+}
+
 // NextGeneration implements [Population].
 func (p *SpeciatedPopulation[T]) NextGeneration() Population[T] {
 	var agentsPerGen int
@@ -103,9 +133,12 @@ func (p *SpeciatedPopulation[T]) NextGeneration() Population[T] {
 	}
 	numSpecies := len(p.species)
 	// Calculate the average fitness of each species, checking if it is the worst.
+	// Species are always visited in order of their ID, as the order of map iteration is random and would stop the run being reproducible.
+	speciesIDs := sortedKeys(p.species)
 	worstFitness := math.Inf(1)
 	worstSpecies := 0
-	for i, agents := range p.species {
+	for _, i := range speciesIDs {
+		agents := p.species[i]
 		var sum float64
 		for _, agent := range agents {
 			sum += agent.Fitness
@@ -121,8 +154,8 @@ func (p *SpeciatedPopulation[T]) NextGeneration() Population[T] {
 		newId  int
 	}
 	toReproduce := make([]speciesToReproduce, 0, numSpecies)
-	deleteWorst := rand.Float64() < p.removeWorstSpeciesChance
-	for id := range p.species {
+	deleteWorst := p.rng.Float64() < p.removeWorstSpeciesChance
+	for _, id := range speciesIDs {
 		if id == worstSpecies && deleteWorst {
 			continue
 		}
@@ -130,7 +163,7 @@ func (p *SpeciatedPopulation[T]) NextGeneration() Population[T] {
 	}
 	if deleteWorst {
 		// pick and index of the species we already know are going to reproduce
-		fromIndex := rand.Intn(len(toReproduce))
+		fromIndex := p.rng.IntN(len(toReproduce))
 		// give that species a new id in the next generation
 		toReproduce[fromIndex].newId = p.counter.Next()
 		// using the same parent species, add a new species also with a new id
@@ -140,19 +173,19 @@ func (p *SpeciatedPopulation[T]) NextGeneration() Population[T] {
 	newSpecies := make(map[int][]*Agent[T])
 	for _, r := range toReproduce {
 		p.selection.SetAgents(p.species[r.fromId])
-		newAgents := make([]*Agent[T], agentsPerGen)
-		for i := range newAgents {
-			parents := SelectNGenotypes(p.selection, p.reproduction.NumParents())
-			newAgents[i] = NewAgent(p.reproduction.Reproduce(parents))
+		newAgents := copyElites(p.species[r.fromId], p.numElites)
+		for len(newAgents) < agentsPerGen {
+			parents := SelectN(p.selection, p.reproduction.NumParents())
+			newAgents = append(newAgents, NewAgent(ReproduceAgents(p.reproduction, parents)))
 		}
 		newSpecies[r.newId] = newAgents
 	}
-	// Swap agents between species
-	numToSwap := int(math.Round(math.Abs(rand.NormFloat64()) * float64(p.stdNumAgentsSwap)))
+	// Swap agents between species, leaving the elites at the start of each species in place
+	numToSwap := int(math.Round(math.Abs(p.rng.NormFloat64()) * float64(p.stdNumAgentsSwap)))
 	for i := 0; i < numToSwap; i++ {
-		aIndex, bIndex := rand.Intn(len(toReproduce)), rand.Intn(len(toReproduce))
+		aIndex, bIndex := p.rng.IntN(len(toReproduce)), p.rng.IntN(len(toReproduce))
 		aId, bId := toReproduce[aIndex].newId, toReproduce[bIndex].newId
-		aAgentIndex, bAgentIndex := rand.Intn(agentsPerGen), rand.Intn(agentsPerGen)
+		aAgentIndex, bAgentIndex := p.numElites+p.rng.IntN(agentsPerGen-p.numElites), p.numElites+p.rng.IntN(agentsPerGen-p.numElites)
 		newSpecies[aId][aAgentIndex], newSpecies[bId][bAgentIndex] = newSpecies[bId][bAgentIndex], newSpecies[aId][aAgentIndex]
 	}
 	// Sanity checks
@@ -178,21 +211,40 @@ func (p *SpeciatedPopulation[T]) NextGeneration() Population[T] {
 		species:                  newSpecies,
 		removeWorstSpeciesChance: p.removeWorstSpeciesChance,
 		stdNumAgentsSwap:         p.stdNumAgentsSwap,
+		rng:                      p.rng,
 		counter:                  p.counter,
 		selection:                p.selection,
 		reproduction:             p.reproduction,
+		numElites:                p.numElites,
+		reevaluateElites:         p.reevaluateElites,
+		numCarried:               p.numElites,
 	}
 }
 
 // All implements [Population].
+// The agents are ordered by the ID of their species.
 func (p *SpeciatedPopulation[T]) All() []*Agent[T] {
-	all := make([]*Agent[T], 0, len(p.species)*len(p.species[0]))
-	for _, agents := range p.species {
-		all = append(all, agents...)
+	all := make([]*Agent[T], 0)
+	for _, id := range sortedKeys(p.species) {
+		all = append(all, p.species[id]...)
 	}
 	return all
 }
 
+// Unevaluated implements [IncrementalPopulation].
+// It returns every agent except the elites copied from the last generation, unless they should be re-evaluated.
+// The agents are ordered by the ID of their species.
+func (p *SpeciatedPopulation[T]) Unevaluated() []*Agent[T] {
+	if p.reevaluateElites {
+		return p.All()
+	}
+	unevaluated := make([]*Agent[T], 0)
+	for _, id := range sortedKeys(p.species) {
+		unevaluated = append(unevaluated, p.species[id][p.numCarried:]...)
+	}
+	return unevaluated
+}
+
 func (p *SpeciatedPopulation[T]) AllSpecies() map[int][]*Agent[T] {
 	res := make(map[int][]*Agent[T])
 	for id, agents := range p.species {
@@ -0,0 +1,110 @@
+package goevo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// csvStatsWriter is a [StatsWriter] that writes each record as a row of a CSV file, with a header on the first row.
+type csvStatsWriter struct {
+	w             *csv.Writer
+	writtenHeader bool
+}
+
+// NewCSVStatsWriter creates a new [StatsWriter] that writes CSV to w.
+// Each record is flushed as soon as it is written, so the file is always up to date.
+func NewCSVStatsWriter(w io.Writer) StatsWriter {
+	if w == nil {
+		panic("cannot have nil writer")
+	}
+	return &csvStatsWriter{
+		w: csv.NewWriter(w),
+	}
+}
+
+// Write implements [StatsWriter].
+func (c *csvStatsWriter) Write(s GenerationStats) error {
+	if !c.writtenHeader {
+		header := []string{"generation", "num_agents", "best", "mean", "median", "worst", "std", "num_species", "mean_hidden_neurons", "mean_synapses"}
+		if err := c.w.Write(header); err != nil {
+			return err
+		}
+		c.writtenHeader = true
+	}
+	f := func(x float64) string { return strconv.FormatFloat(x, 'g', -1, 64) }
+	row := []string{
+		strconv.Itoa(s.Generation),
+		strconv.Itoa(s.NumAgents),
+		f(s.Best),
+		f(s.Mean),
+		f(s.Median),
+		f(s.Worst),
+		f(s.Std),
+		strconv.Itoa(s.NumSpecies),
+		f(s.MeanHiddenNeurons),
+		f(s.MeanSynapses),
+	}
+	if err := c.w.Write(row); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonStatsWriter is a [StatsWriter] that writes each record as a JSON object on its own line.
+type jsonStatsWriter struct {
+	enc *json.Encoder
+}
+
+// NewJSONStatsWriter creates a new [StatsWriter] that writes JSON-lines to w.
+// JSON has no way to represent NaN or infinite numbers, so they are written as null.
+func NewJSONStatsWriter(w io.Writer) StatsWriter {
+	if w == nil {
+		panic("cannot have nil writer")
+	}
+	return &jsonStatsWriter{
+		enc: json.NewEncoder(w),
+	}
+}
+
+// Write implements [StatsWriter].
+func (j *jsonStatsWriter) Write(s GenerationStats) error {
+	record := struct {
+		Generation        int       `json:"generation"`
+		NumAgents         int       `json:"num_agents"`
+		Best              jsonFloat `json:"best"`
+		Mean              jsonFloat `json:"mean"`
+		Median            jsonFloat `json:"median"`
+		Worst             jsonFloat `json:"worst"`
+		Std               jsonFloat `json:"std"`
+		NumSpecies        int       `json:"num_species"`
+		MeanHiddenNeurons jsonFloat `json:"mean_hidden_neurons"`
+		MeanSynapses      jsonFloat `json:"mean_synapses"`
+	}{
+		s.Generation,
+		s.NumAgents,
+		jsonFloat(s.Best),
+		jsonFloat(s.Mean),
+		jsonFloat(s.Median),
+		jsonFloat(s.Worst),
+		jsonFloat(s.Std),
+		s.NumSpecies,
+		jsonFloat(s.MeanHiddenNeurons),
+		jsonFloat(s.MeanSynapses),
+	}
+	return j.enc.Encode(&record)
+}
+
+// jsonFloat is a float64 that is encoded as null in JSON if it is NaN or infinite.
+type jsonFloat float64
+
+// MarshalJSON implements [json.Marshaler].
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(f))
+}
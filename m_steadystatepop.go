@@ -0,0 +1,204 @@
+package goevo
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// SteadyStatePopulation is a population that creates a few children at a time, instead of a whole new generation.
+// Once a child has been evaluated, it is inserted into the population, replacing the member chosen by a [Replacement].
+//
+// Used with a [Runner], each generation evaluates only the new children, as the population is an [IncrementalPopulation].
+// Children can also be bred and inserted one at a time with [SteadyStatePopulation.Breed] and [SteadyStatePopulation.Insert],
+// or with [SteadyStatePopulation.EvaluateAsync], which suits evaluations that take different amounts of time.
+type SteadyStatePopulation[T any] struct {
+	lock         *sync.Mutex
+	size         int
+	numChildren  int
+	members      []*Agent[T]
+	children     []*Agent[T]
+	selection    Selection[T]
+	reproduction Reproduction[T]
+	replacement  Replacement[T]
+}
+
+// NewSteadyStatePopulation creates a new SteadyStatePopulation of n members, each with a new genotype created by newGenotype.
+// All n initial agents are unevaluated, and are inserted once they have been evaluated.
+// After that, each generation breeds numChildren children from the members.
+func NewSteadyStatePopulation[T any](
+	newGenotype func() T,
+	n int,
+	numChildren int,
+	selection Selection[T],
+	reproduction Reproduction[T],
+	replacement Replacement[T],
+) *SteadyStatePopulation[T] {
+	if n <= 0 {
+		panic("cannot create population with less than 1 member")
+	}
+	if numChildren <= 0 {
+		panic("must create at least 1 child per generation")
+	}
+	if selection == nil {
+		panic("cannot have nil selection")
+	}
+	if reproduction == nil {
+		panic("cannot have nil reproduction")
+	}
+	if replacement == nil {
+		panic("cannot have nil replacement")
+	}
+	children := make([]*Agent[T], n)
+	for i := range children {
+		children[i] = NewAgent(newGenotype())
+	}
+	return &SteadyStatePopulation[T]{
+		lock:         &sync.Mutex{},
+		size:         n,
+		numChildren:  numChildren,
+		members:      make([]*Agent[T], 0, n),
+		children:     children,
+		selection:    selection,
+		reproduction: reproduction,
+		replacement:  replacement,
+	}
+}
+
+// NextGeneration implements [Population].
+// It inserts every unevaluated child, which must now have been evaluated, then breeds new children.
+func (p *SteadyStatePopulation[T]) NextGeneration() Population[T] {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	next := &SteadyStatePopulation[T]{
+		lock:         &sync.Mutex{},
+		size:         p.size,
+		numChildren:  p.numChildren,
+		members:      slices.Clone(p.members),
+		selection:    p.selection,
+		reproduction: p.reproduction,
+		replacement:  p.replacement,
+	}
+	for _, c := range p.children {
+		next.insert(c)
+	}
+	next.children = make([]*Agent[T], p.numChildren)
+	for i := range next.children {
+		next.children[i] = next.breed()
+	}
+	return next
+}
+
+// All implements [Population].
+// It returns the members from oldest to newest, followed by the unevaluated children.
+func (p *SteadyStatePopulation[T]) All() []*Agent[T] {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append(slices.Clone(p.members), p.children...)
+}
+
+// Unevaluated implements [IncrementalPopulation].
+func (p *SteadyStatePopulation[T]) Unevaluated() []*Agent[T] {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return slices.Clone(p.children)
+}
+
+// Members returns the evaluated members of the population, from oldest to newest.
+func (p *SteadyStatePopulation[T]) Members() []*Agent[T] {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return slices.Clone(p.members)
+}
+
+// Breed returns a new agent that should be evaluated and then passed to [SteadyStatePopulation.Insert].
+// If there are unevaluated children waiting, one of those is returned instead of breeding a new one.
+// Unlike [SteadyStatePopulation.NextGeneration], this modifies the population in place. It is safe to call from multiple goroutines.
+func (p *SteadyStatePopulation[T]) Breed() *Agent[T] {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.children) > 0 {
+		c := p.children[0]
+		p.children = p.children[1:]
+		return c
+	}
+	return p.breed()
+}
+
+// Insert adds an evaluated child to the population, returning false if the replacement discarded it.
+// Until the population is full, children are always added.
+// Unlike [SteadyStatePopulation.NextGeneration], this modifies the population in place. It is safe to call from multiple goroutines.
+func (p *SteadyStatePopulation[T]) Insert(child *Agent[T]) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.insert(child)
+}
+
+// EvaluateAsync keeps up to workers evaluations running at once, breeding a new child as soon as one finishes and inserting the finished one.
+// It returns once numEvaluations children have been evaluated, or early with the error of ctx if it is cancelled.
+// Evaluations that are running when ctx is cancelled are waited for and inserted.
+func (p *SteadyStatePopulation[T]) EvaluateAsync(ctx context.Context, evaluate func(context.Context, *Agent[T]), workers int, numEvaluations int) error {
+	if evaluate == nil {
+		panic("cannot have nil evaluate function")
+	}
+	if workers <= 0 {
+		panic("must have at least one worker")
+	}
+	done := make(chan *Agent[T])
+	started, running := 0, 0
+	start := func() {
+		child := p.Breed()
+		started++
+		running++
+		go func() {
+			evaluate(ctx, child)
+			done <- child
+		}()
+	}
+	// Until the first members have been inserted, only the waiting children can be started.
+	// The remaining workers are started as the inserts arrive.
+	startAll := func() {
+		for ctx.Err() == nil && started < numEvaluations && running < workers && p.canBreed() {
+			start()
+		}
+	}
+	startAll()
+	for running > 0 {
+		child := <-done
+		running--
+		p.Insert(child)
+		startAll()
+	}
+	return ctx.Err()
+}
+
+// canBreed returns whether [SteadyStatePopulation.Breed] has a child to return.
+func (p *SteadyStatePopulation[T]) canBreed() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.children) > 0 || len(p.members) > 0
+}
+
+// breed creates a new child from the members. The lock must be held.
+func (p *SteadyStatePopulation[T]) breed() *Agent[T] {
+	if len(p.members) == 0 {
+		panic("cannot breed before any members have been inserted")
+	}
+	p.selection.SetAgents(p.members)
+	parents := SelectN(p.selection, p.reproduction.NumParents())
+	return NewAgent(ReproduceAgents(p.reproduction, parents))
+}
+
+// insert adds the child to the members, keeping them ordered from oldest to newest. The lock must be held.
+func (p *SteadyStatePopulation[T]) insert(child *Agent[T]) bool {
+	if len(p.members) < p.size {
+		p.members = append(p.members, child)
+		return true
+	}
+	i := p.replacement.Replace(p.members, child)
+	if i < 0 {
+		return false
+	}
+	p.members = append(slices.Delete(p.members, i, i+1), child)
+	return true
+}
@@ -0,0 +1,84 @@
+package goevo
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// Check that each replacement policy picks the expected member
+func TestReplacements(t *testing.T) {
+	members := []*Agent[int]{{Fitness: 3}, {Fitness: 1}, {Fitness: 1}, {Fitness: 5}}
+	assertEq(t, NewReplaceWorst[int]().Replace(members, &Agent[int]{Fitness: 2}), 1, "worst")
+	assertEq(t, NewReplaceOldest[int]().Replace(members, &Agent[int]{Fitness: 2}), 0, "oldest")
+	assertEq(t, NewReplaceTournamentLoser[int](NewRand(0), len(members)*10).Replace(members, &Agent[int]{Fitness: 2}) != 3, true, "tournament loser")
+	ifBetter := NewReplaceIfBetter(NewReplaceWorst[int]())
+	assertEq(t, ifBetter.Replace(members, &Agent[int]{Fitness: 2}), 1, "better child")
+	assertEq(t, ifBetter.Replace(members, &Agent[int]{Fitness: 1}), -1, "equal child")
+}
+
+func setupSteadyStateTestStuff(seed uint64) *SteadyStatePopulation[*ArrayGenotype[float64]] {
+	rng := NewRand(seed)
+	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0.0, 0.05), 0.1)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverUniform[float64](rng), mut)
+	return NewSteadyStatePopulation(func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(5, NewGeneratorNormal(rng, 0.0, 0.5))
+	}, 30, 2, NewTournamentSelection[*ArrayGenotype[float64]](rng, 3), reprod, NewReplaceIfBetter(NewReplaceWorst[*ArrayGenotype[float64]]()))
+}
+
+func steadyStateTestFitness(g *ArrayGenotype[float64]) float64 {
+	total := 0.0
+	for i := range g.Len() {
+		total -= math.Abs(g.At(i) - 1)
+	}
+	return total
+}
+
+// Check that the runner only evaluates the new children of a steady state population
+func TestSteadyStatePopulationRunner(t *testing.T) {
+	best, summary := NewRunner(steadyStateTestFitness, 4, NewStopTargetFitness(-0.1), NewStopMaxGenerations(20000)).Run(setupSteadyStateTestStuff(0))
+	assertEq(t, summary.Evaluations, 30+(summary.Generations-1)*2, "evaluations")
+	if best.Fitness < -0.1 {
+		t.Fatalf("steady state population did not converge, best fitness %v", best.Fitness)
+	}
+}
+
+// Check that asynchronous evaluation inserts every child and improves the population
+func TestSteadyStatePopulationAsync(t *testing.T) {
+	pop := setupSteadyStateTestStuff(1)
+	evaluate := func(_ context.Context, a *Agent[*ArrayGenotype[float64]]) {
+		a.Fitness = steadyStateTestFitness(a.Genotype)
+	}
+	if err := pop.EvaluateAsync(context.Background(), evaluate, 4, 30); err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, len(pop.Members()), 30, "members after initial evaluation")
+	assertEq(t, len(pop.Unevaluated()), 0, "unevaluated after initial evaluation")
+	worst := func() float64 {
+		w := math.Inf(1)
+		for _, a := range pop.Members() {
+			w = math.Min(w, a.Fitness)
+		}
+		return w
+	}
+	before := worst()
+	if err := pop.EvaluateAsync(context.Background(), evaluate, 4, 3000); err != nil {
+		t.Fatal(err)
+	}
+	if worst() <= before {
+		t.Fatalf("worst fitness did not improve: %v -> %v", before, worst())
+	}
+}
+
+// Check that asynchronous evaluation of a new population works with more workers than initial children
+func TestSteadyStatePopulationAsyncManyWorkers(t *testing.T) {
+	pop := setupSteadyStateTestStuff(2)
+	evaluate := func(_ context.Context, a *Agent[*ArrayGenotype[float64]]) {
+		a.Fitness = steadyStateTestFitness(a.Genotype)
+	}
+	if err := pop.EvaluateAsync(context.Background(), evaluate, 64, 100); err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, len(pop.Members()), 30, "members")
+	assertEq(t, len(pop.Unevaluated()), 0, "unevaluated")
+}
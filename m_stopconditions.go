@@ -0,0 +1,96 @@
+package goevo
+
+import (
+	"fmt"
+	"time"
+)
+
+// stopTargetFitness is a [StopCondition] that stops once the best fitness reaches a target.
+type stopTargetFitness struct {
+	target float64
+}
+
+// NewStopTargetFitness creates a [StopCondition] that stops once the best fitness is greater than or equal to target.
+func NewStopTargetFitness(target float64) StopCondition {
+	return &stopTargetFitness{target: target}
+}
+
+// ShouldStop implements [StopCondition].
+func (s *stopTargetFitness) ShouldStop(summary RunSummary) bool {
+	return summary.BestFitness >= s.target
+}
+
+// String implements [StopCondition].
+func (s *stopTargetFitness) String() string {
+	return fmt.Sprintf("reached target fitness %v", s.target)
+}
+
+// stopMaxGenerations is a [StopCondition] that stops after a fixed number of generations.
+type stopMaxGenerations struct {
+	generations int
+}
+
+// NewStopMaxGenerations creates a [StopCondition] that stops once the given number of generations have been evaluated.
+func NewStopMaxGenerations(generations int) StopCondition {
+	if generations <= 0 {
+		panic("must have at least one generation")
+	}
+	return &stopMaxGenerations{generations: generations}
+}
+
+// ShouldStop implements [StopCondition].
+func (s *stopMaxGenerations) ShouldStop(summary RunSummary) bool {
+	return summary.Generations >= s.generations
+}
+
+// String implements [StopCondition].
+func (s *stopMaxGenerations) String() string {
+	return fmt.Sprintf("reached %v generations", s.generations)
+}
+
+// stopMaxDuration is a [StopCondition] that stops once a wall-clock time limit is exceeded.
+type stopMaxDuration struct {
+	duration time.Duration
+}
+
+// NewStopMaxDuration creates a [StopCondition] that stops once the run has taken at least the given duration.
+// The duration is only checked between generations, so a run may overshoot by up to one generation.
+func NewStopMaxDuration(duration time.Duration) StopCondition {
+	if duration <= 0 {
+		panic("cannot have duration <= 0")
+	}
+	return &stopMaxDuration{duration: duration}
+}
+
+// ShouldStop implements [StopCondition].
+func (s *stopMaxDuration) ShouldStop(summary RunSummary) bool {
+	return summary.Duration >= s.duration
+}
+
+// String implements [StopCondition].
+func (s *stopMaxDuration) String() string {
+	return fmt.Sprintf("exceeded duration %v", s.duration)
+}
+
+// stopStagnation is a [StopCondition] that stops when the best fitness has not improved for a number of generations.
+type stopStagnation struct {
+	generations int
+}
+
+// NewStopStagnation creates a [StopCondition] that stops once the best fitness has not improved for the given number of generations.
+func NewStopStagnation(generations int) StopCondition {
+	if generations <= 0 {
+		panic("must have at least one generation")
+	}
+	return &stopStagnation{generations: generations}
+}
+
+// ShouldStop implements [StopCondition].
+func (s *stopStagnation) ShouldStop(summary RunSummary) bool {
+	return summary.Generations-summary.BestGeneration >= s.generations
+}
+
+// String implements [StopCondition].
+func (s *stopStagnation) String() string {
+	return fmt.Sprintf("no improvement for %v generations", s.generations)
+}
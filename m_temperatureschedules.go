@@ -0,0 +1,120 @@
+package goevo
+
+import "math"
+
+// exponentialSchedule is a [TemperatureSchedule] that multiplies the temperature by a fixed decay every step.
+type exponentialSchedule struct {
+	temperature float64
+	decay       float64
+}
+
+// NewExponentialSchedule creates a new [TemperatureSchedule] that starts at initial and is multiplied by decay every step.
+// The decay should be between 0 and 1, and is usually close to 1.
+func NewExponentialSchedule(initial, decay float64) TemperatureSchedule {
+	if initial < 0 {
+		panic("cannot have a negative temperature")
+	}
+	if decay <= 0 || decay > 1 {
+		panic("decay must be between 0 and 1")
+	}
+	return &exponentialSchedule{
+		temperature: initial,
+		decay:       decay,
+	}
+}
+
+// Temperature implements [TemperatureSchedule].
+func (s *exponentialSchedule) Temperature() float64 {
+	return s.temperature
+}
+
+// Step implements [TemperatureSchedule].
+func (s *exponentialSchedule) Step(bool) {
+	s.temperature *= s.decay
+}
+
+// linearSchedule is a [TemperatureSchedule] that reduces the temperature by a fixed amount every step, until it reaches 0.
+type linearSchedule struct {
+	temperature float64
+	decrement   float64
+}
+
+// NewLinearSchedule creates a new [TemperatureSchedule] that starts at initial and falls linearly to 0 over numSteps steps.
+func NewLinearSchedule(initial float64, numSteps int) TemperatureSchedule {
+	if initial < 0 {
+		panic("cannot have a negative temperature")
+	}
+	if numSteps <= 0 {
+		panic("must have at least one step")
+	}
+	return &linearSchedule{
+		temperature: initial,
+		decrement:   initial / float64(numSteps),
+	}
+}
+
+// Temperature implements [TemperatureSchedule].
+func (s *linearSchedule) Temperature() float64 {
+	return s.temperature
+}
+
+// Step implements [TemperatureSchedule].
+func (s *linearSchedule) Step(bool) {
+	s.temperature = math.Max(0, s.temperature-s.decrement)
+}
+
+// adaptiveSchedule is a [TemperatureSchedule] that adjusts the temperature to keep the acceptance rate near a target.
+type adaptiveSchedule struct {
+	temperature      float64
+	targetAcceptance float64
+	factor           float64
+	window           int
+	steps            int
+	accepted         int
+}
+
+// NewAdaptiveSchedule creates a new [TemperatureSchedule] that starts at initial, and every window steps compares the fraction of candidates
+// that were accepted to targetAcceptance. If more were accepted than the target, the temperature is multiplied by factor (which must be between 0 and 1),
+// otherwise it is divided by factor.
+func NewAdaptiveSchedule(initial, targetAcceptance, factor float64, window int) TemperatureSchedule {
+	if initial <= 0 {
+		panic("must have a temperature greater than 0")
+	}
+	if targetAcceptance <= 0 || targetAcceptance >= 1 {
+		panic("target acceptance must be between 0 and 1")
+	}
+	if factor <= 0 || factor >= 1 {
+		panic("factor must be between 0 and 1")
+	}
+	if window <= 0 {
+		panic("must have a window of at least 1")
+	}
+	return &adaptiveSchedule{
+		temperature:      initial,
+		targetAcceptance: targetAcceptance,
+		factor:           factor,
+		window:           window,
+	}
+}
+
+// Temperature implements [TemperatureSchedule].
+func (s *adaptiveSchedule) Temperature() float64 {
+	return s.temperature
+}
+
+// Step implements [TemperatureSchedule].
+func (s *adaptiveSchedule) Step(accepted bool) {
+	s.steps++
+	if accepted {
+		s.accepted++
+	}
+	if s.steps < s.window {
+		return
+	}
+	if float64(s.accepted)/float64(s.steps) > s.targetAcceptance {
+		s.temperature *= s.factor
+	} else {
+		s.temperature /= s.factor
+	}
+	s.steps, s.accepted = 0, 0
+}
@@ -0,0 +1,71 @@
+package goevo
+
+import "slices"
+
+// ringTopology is a [MigrationTopology] where each island sends migrants to the next island, and the last island sends to the first.
+type ringTopology struct{}
+
+// NewRingTopology creates a new [MigrationTopology] where the islands form a one-directional ring.
+func NewRingTopology() MigrationTopology {
+	return &ringTopology{}
+}
+
+// Destinations implements [MigrationTopology].
+func (t *ringTopology) Destinations(from, numIslands int) []int {
+	if numIslands <= 1 {
+		return nil
+	}
+	return []int{(from + 1) % numIslands}
+}
+
+// fullyConnectedTopology is a [MigrationTopology] where each island sends migrants to every other island.
+type fullyConnectedTopology struct{}
+
+// NewFullyConnectedTopology creates a new [MigrationTopology] where every island sends migrants to every other island.
+func NewFullyConnectedTopology() MigrationTopology {
+	return &fullyConnectedTopology{}
+}
+
+// Destinations implements [MigrationTopology].
+func (t *fullyConnectedTopology) Destinations(from, numIslands int) []int {
+	dests := make([]int, 0, numIslands-1)
+	for i := range numIslands {
+		if i != from {
+			dests = append(dests, i)
+		}
+	}
+	return dests
+}
+
+// customTopology is a [MigrationTopology] defined by a fixed list of destinations for each island.
+type customTopology struct {
+	destinations [][]int
+}
+
+// NewCustomTopology creates a new [MigrationTopology] where island i sends migrants to each island in destinations[i].
+// The number of islands must match the length of destinations.
+func NewCustomTopology(destinations [][]int) MigrationTopology {
+	for i, dests := range destinations {
+		for _, d := range dests {
+			if d < 0 || d >= len(destinations) {
+				panic("destination island out of range")
+			}
+			if d == i {
+				panic("island cannot send migrants to itself")
+			}
+		}
+	}
+	ds := make([][]int, len(destinations))
+	for i := range ds {
+		ds[i] = slices.Clone(destinations[i])
+	}
+	return &customTopology{destinations: ds}
+}
+
+// Destinations implements [MigrationTopology].
+func (t *customTopology) Destinations(from, numIslands int) []int {
+	if numIslands != len(t.destinations) {
+		panic("custom topology has a different number of islands to the population")
+	}
+	return t.destinations[from]
+}
@@ -1,22 +1,27 @@
 package goevo
 
 import (
-	"math/rand"
+	"math/rand/v2"
 )
 
 // tournamentSelection is a tournamentSelection strategy that selects the best agent from a random tournament of agents.
 // It implements [tournamentSelection].
 type tournamentSelection[T any] struct {
+	rng *rand.Rand
 	// The number of agents to include in each tournament.
 	tournamentSize int
 	agents         []*Agent[T]
 }
 
-func NewTournamentSelection[T any](tournamentSize int) Selection[T] {
+func NewTournamentSelection[T any](rng *rand.Rand, tournamentSize int) Selection[T] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
 	if tournamentSize <= 0 {
 		panic("must have at least tournament size of 1")
 	}
 	return &tournamentSelection[T]{
+		rng:            rng,
 		tournamentSize: tournamentSize,
 		agents:         nil,
 	}
@@ -35,9 +40,9 @@ func (t *tournamentSelection[T]) Select() *Agent[T] {
 	if len(t.agents) == 0 {
 		panic("must have at least one agent")
 	}
-	best := t.agents[rand.Intn(len(t.agents))]
+	best := t.agents[t.rng.IntN(len(t.agents))]
 	for i := 0; i < t.tournamentSize-1; i++ {
-		testIndex := rand.Intn(len(t.agents))
+		testIndex := t.rng.IntN(len(t.agents))
 		if t.agents[testIndex].Fitness > best.Fitness {
 			best = t.agents[testIndex]
 		}
@@ -0,0 +1,478 @@
+package goevo
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TreeFunction is a function that can be used in the internal nodes of a [TreeGenotype].
+type TreeFunction struct {
+	// Name is used when printing the tree.
+	Name string
+	// Arity is the number of arguments the function takes, which is the number of children its node has.
+	Arity int
+	// Apply calculates the result of the function. It is always given Arity arguments.
+	Apply func(args []float64) float64
+}
+
+// Some common functions for a [TreeGenotype].
+var (
+	TreeAdd = TreeFunction{"add", 2, func(a []float64) float64 { return a[0] + a[1] }}
+	TreeSub = TreeFunction{"sub", 2, func(a []float64) float64 { return a[0] - a[1] }}
+	TreeMul = TreeFunction{"mul", 2, func(a []float64) float64 { return a[0] * a[1] }}
+	// TreeDiv is protected division, which returns 1 when dividing by (nearly) 0, so that every tree has a finite result.
+	TreeDiv = TreeFunction{"div", 2, func(a []float64) float64 {
+		if math.Abs(a[1]) < 1e-9 {
+			return 1
+		}
+		return a[0] / a[1]
+	}}
+	TreeSin = TreeFunction{"sin", 1, func(a []float64) float64 { return math.Sin(a[0]) }}
+	TreeCos = TreeFunction{"cos", 1, func(a []float64) float64 { return math.Cos(a[0]) }}
+)
+
+// TreePrimitives is the function set and terminal set that a [TreeGenotype] is built from.
+// The terminals are the input variables, and optionally ephemeral random constants, which are created once when their node is created and then kept.
+type TreePrimitives struct {
+	functions    []TreeFunction
+	numVariables int
+	constants    Generator[float64]
+}
+
+// NewTreePrimitives creates a new [TreePrimitives] with numVariables input variables and the given functions.
+// If constants is not nil, ephemeral random constants are also terminals, and their values are drawn from it.
+func NewTreePrimitives(numVariables int, constants Generator[float64], functions ...TreeFunction) *TreePrimitives {
+	if numVariables < 0 {
+		panic("cannot have negative number of variables")
+	}
+	if numVariables == 0 && constants == nil {
+		panic("must have at least one variable or constants")
+	}
+	if len(functions) == 0 {
+		panic("must have at least one function")
+	}
+	for _, f := range functions {
+		if f.Arity <= 0 {
+			panic("functions must have an arity of at least 1")
+		}
+		if f.Apply == nil {
+			panic("cannot have nil function")
+		}
+	}
+	return &TreePrimitives{
+		functions:    slices.Clone(functions),
+		numVariables: numVariables,
+		constants:    constants,
+	}
+}
+
+// numTerminals returns the number of kinds of terminal. Ephemeral random constants count as one kind.
+func (p *TreePrimitives) numTerminals() int {
+	if p.constants != nil {
+		return p.numVariables + 1
+	}
+	return p.numVariables
+}
+
+// randomTerminal returns a new random terminal node.
+func (p *TreePrimitives) randomTerminal(rng *rand.Rand) treeNode {
+	v := rng.IntN(p.numTerminals())
+	if v == p.numVariables {
+		return treeNode{function: -1, variable: -1, constant: p.constants.Next()}
+	}
+	return treeNode{function: -1, variable: v}
+}
+
+// randomTree returns the nodes of a new random tree with at most the given depth.
+// If full is true every branch has exactly that depth, otherwise each node below the depth is chosen from all functions and terminals (grow).
+func (p *TreePrimitives) randomTree(rng *rand.Rand, depth int, full bool) []treeNode {
+	if depth == 0 || (!full && rng.IntN(len(p.functions)+p.numTerminals()) >= len(p.functions)) {
+		return []treeNode{p.randomTerminal(rng)}
+	}
+	f := rng.IntN(len(p.functions))
+	nodes := []treeNode{{function: f, variable: -1}}
+	for range p.functions[f].Arity {
+		nodes = append(nodes, p.randomTree(rng, depth-1, full)...)
+	}
+	return nodes
+}
+
+// treeNode is a node of a [TreeGenotype].
+type treeNode struct {
+	// function is the index of the function of an internal node, or -1 for a terminal.
+	function int
+	// variable is the index of the input of a variable terminal, or -1 for a constant terminal or internal node.
+	variable int
+	// constant is the value of a constant terminal.
+	constant float64
+}
+
+// TreeGenotype is a genotype for Koza-style genetic programming, where the genotype is an expression tree of functions and terminals.
+// The tree is evaluated on the inputs given to [TreeGenotype.Forward], and its single result is the output.
+//
+// The nodes are stored in prefix order, so every subtree is a contiguous range of nodes.
+// The depth of a tree with only a terminal is 0.
+type TreeGenotype struct {
+	primitives *TreePrimitives
+	nodes      []treeNode
+}
+
+// NewTreeGenotypeFull creates a new random [TreeGenotype] where every terminal is at exactly the given depth.
+func NewTreeGenotypeFull(rng *rand.Rand, primitives *TreePrimitives, depth int) *TreeGenotype {
+	return newTreeGenotypeRandom(rng, primitives, depth, true)
+}
+
+// NewTreeGenotypeGrow creates a new random [TreeGenotype] with a depth of at most the given depth,
+// where each node above that depth is a random choice from every function and terminal.
+func NewTreeGenotypeGrow(rng *rand.Rand, primitives *TreePrimitives, depth int) *TreeGenotype {
+	return newTreeGenotypeRandom(rng, primitives, depth, false)
+}
+
+func newTreeGenotypeRandom(rng *rand.Rand, primitives *TreePrimitives, depth int, full bool) *TreeGenotype {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if primitives == nil {
+		panic("cannot have nil primitives")
+	}
+	if depth < 0 {
+		panic("cannot have negative depth")
+	}
+	return &TreeGenotype{
+		primitives: primitives,
+		nodes:      primitives.randomTree(rng, depth, full),
+	}
+}
+
+// NewTreeRampedHalfAndHalf returns a function that creates random [TreeGenotype]s with ramped half-and-half initialisation,
+// to be used as the newGenotype function of a population.
+// Successive trees cycle through every depth from minDepth to maxDepth, and alternate between the full and grow methods at each depth,
+// so the population starts with a wide variety of shapes and sizes.
+func NewTreeRampedHalfAndHalf(rng *rand.Rand, primitives *TreePrimitives, minDepth, maxDepth int) func() *TreeGenotype {
+	if minDepth < 0 || maxDepth < minDepth {
+		panic("depths must be at least 0, and max depth must be at least min depth")
+	}
+	i := 0
+	return func() *TreeGenotype {
+		depth := minDepth + (i/2)%(maxDepth-minDepth+1)
+		full := i%2 == 0
+		i++
+		return newTreeGenotypeRandom(rng, primitives, depth, full)
+	}
+}
+
+// Forward implements [Forwarder] by evaluating the tree on the inputs, returning a single output.
+func (g *TreeGenotype) Forward(inputs []float64) []float64 {
+	if len(inputs) != g.primitives.numVariables {
+		panic("incorrect number of inputs")
+	}
+	result, _ := g.evaluate(inputs, 0)
+	return []float64{result}
+}
+
+// evaluate returns the result of the subtree starting at node i, and the index of the node after that subtree.
+func (g *TreeGenotype) evaluate(inputs []float64, i int) (float64, int) {
+	n := g.nodes[i]
+	if n.function == -1 {
+		if n.variable == -1 {
+			return n.constant, i + 1
+		}
+		return inputs[n.variable], i + 1
+	}
+	f := g.primitives.functions[n.function]
+	args := make([]float64, f.Arity)
+	next := i + 1
+	for a := range args {
+		args[a], next = g.evaluate(inputs, next)
+	}
+	return f.Apply(args), next
+}
+
+// arity returns the number of children of the node.
+func (g *TreeGenotype) arity(n treeNode) int {
+	if n.function == -1 {
+		return 0
+	}
+	return g.primitives.functions[n.function].Arity
+}
+
+// subtreeEnd returns the index after the last node of the subtree starting at node i.
+func (g *TreeGenotype) subtreeEnd(i int) int {
+	for needed := 1; needed > 0; i++ {
+		needed += g.arity(g.nodes[i]) - 1
+	}
+	return i
+}
+
+// nodeDepths returns the depth of every node, where the root has a depth of 0.
+func (g *TreeGenotype) nodeDepths() []int {
+	depths := make([]int, len(g.nodes))
+	// remaining is a stack of the number of children still to be visited for each internal node above the current one
+	remaining := []int{}
+	for i, n := range g.nodes {
+		for len(remaining) > 0 && remaining[len(remaining)-1] == 0 {
+			remaining = remaining[:len(remaining)-1]
+		}
+		if len(remaining) > 0 {
+			remaining[len(remaining)-1]--
+		}
+		depths[i] = len(remaining)
+		if a := g.arity(n); a > 0 {
+			remaining = append(remaining, a)
+		}
+	}
+	return depths
+}
+
+// Size returns the number of nodes in the tree.
+func (g *TreeGenotype) Size() int {
+	return len(g.nodes)
+}
+
+// Depth returns the depth of the tree, which is 0 for a tree with only a terminal.
+func (g *TreeGenotype) Depth() int {
+	return slices.Max(g.nodeDepths())
+}
+
+// replaceSubtree replaces the subtree starting at node i with the given nodes.
+func (g *TreeGenotype) replaceSubtree(i int, nodes []treeNode) {
+	g.nodes = slices.Concat(g.nodes[:i], nodes, g.nodes[g.subtreeEnd(i):])
+}
+
+// Clone implements [Cloneable].
+// The clone shares the same [TreePrimitives].
+func (g *TreeGenotype) Clone() any {
+	return &TreeGenotype{
+		primitives: g.primitives,
+		nodes:      slices.Clone(g.nodes),
+	}
+}
+
+// Validate implements [Validateable].
+// It checks that every node refers to a valid function or variable, and that the nodes form exactly one complete tree.
+func (g *TreeGenotype) Validate() error {
+	if g.primitives == nil {
+		return fmt.Errorf("tree has no primitives")
+	}
+	if len(g.nodes) == 0 {
+		return fmt.Errorf("tree has no nodes")
+	}
+	needed := 1
+	for i, n := range g.nodes {
+		if needed == 0 {
+			return fmt.Errorf("tree has extra nodes after node %v", i-1)
+		}
+		if n.function < -1 || n.function >= len(g.primitives.functions) {
+			return fmt.Errorf("node %v has invalid function %v", i, n.function)
+		}
+		if n.function != -1 && n.variable != -1 {
+			return fmt.Errorf("function node %v has variable %v", i, n.variable)
+		}
+		if n.function == -1 && n.variable == -1 && g.primitives.constants == nil {
+			return fmt.Errorf("node %v is a constant but the primitives have no constants", i)
+		}
+		if n.variable < -1 || n.variable >= g.primitives.numVariables {
+			return fmt.Errorf("node %v has invalid variable %v", i, n.variable)
+		}
+		needed += g.arity(n) - 1
+	}
+	if needed != 0 {
+		return fmt.Errorf("tree is missing %v nodes", needed)
+	}
+	return nil
+}
+
+// String returns the tree in prefix notation, such as "(add x0 (mul x1 2.5))".
+func (g *TreeGenotype) String() string {
+	sb := &strings.Builder{}
+	g.writeString(sb, 0)
+	return sb.String()
+}
+
+// writeString writes the subtree starting at node i, and returns the index of the node after that subtree.
+func (g *TreeGenotype) writeString(sb *strings.Builder, i int) int {
+	n := g.nodes[i]
+	if n.function == -1 {
+		if n.variable == -1 {
+			sb.WriteString(strconv.FormatFloat(n.constant, 'g', 4, 64))
+		} else {
+			fmt.Fprintf(sb, "x%d", n.variable)
+		}
+		return i + 1
+	}
+	f := g.primitives.functions[n.function]
+	sb.WriteString("(" + f.Name)
+	next := i + 1
+	for range f.Arity {
+		sb.WriteString(" ")
+		next = g.writeString(sb, next)
+	}
+	sb.WriteString(")")
+	return next
+}
+
+// randomCrossoverPoint returns a random node, choosing an internal node 90% of the time if there are any, as recommended by Koza.
+// This stops crossover from mostly swapping single terminals, as about half of the nodes in a tree are terminals.
+func (g *TreeGenotype) randomCrossoverPoint(rng *rand.Rand) int {
+	var internal, terminals []int
+	for i, n := range g.nodes {
+		if n.function == -1 {
+			terminals = append(terminals, i)
+		} else {
+			internal = append(internal, i)
+		}
+	}
+	if len(internal) > 0 && rng.Float64() < 0.9 {
+		return internal[rng.IntN(len(internal))]
+	}
+	return terminals[rng.IntN(len(terminals))]
+}
+
+// treeCrossoverSubtree is a crossover that replaces a random subtree of one parent with a random subtree of the other.
+type treeCrossoverSubtree struct {
+	rng      *rand.Rand
+	maxDepth int
+}
+
+// NewTreeCrossoverSubtree creates a new subtree crossover for [TreeGenotype]s, which requires two parents.
+// A random subtree of the first parent is replaced by a random subtree of the second.
+// If the child would be deeper than maxDepth, new subtrees are tried a few times, and if they all fail the child is a copy of the first parent.
+func NewTreeCrossoverSubtree(rng *rand.Rand, maxDepth int) Crossover[*TreeGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if maxDepth < 0 {
+		panic("cannot have negative max depth")
+	}
+	return &treeCrossoverSubtree{
+		rng:      rng,
+		maxDepth: maxDepth,
+	}
+}
+
+// Crossover implements [Crossover].
+func (c *treeCrossoverSubtree) Crossover(gs []*TreeGenotype) *TreeGenotype {
+	if len(gs) != 2 {
+		panic("subtree crossover requires exactly 2 parents")
+	}
+	a, b := gs[0], gs[1]
+	if a.primitives != b.primitives {
+		panic("parents must have the same primitives for subtree crossover")
+	}
+	depthsA, depthsB := a.nodeDepths(), b.nodeDepths()
+	for range 10 {
+		i, j := a.randomCrossoverPoint(c.rng), b.randomCrossoverPoint(c.rng)
+		end := b.subtreeEnd(j)
+		// The depth of the donated subtree is measured from its root
+		if depthsA[i]+slices.Max(depthsB[j:end])-depthsB[j] > c.maxDepth {
+			continue
+		}
+		child := Clone(a)
+		child.replaceSubtree(i, b.nodes[j:end])
+		return child
+	}
+	return Clone(a)
+}
+
+// NumParents implements [Crossover].
+func (c *treeCrossoverSubtree) NumParents() int {
+	return 2
+}
+
+// treeMutationSubtree is a mutation that replaces a random subtree with a new random one.
+type treeMutationSubtree struct {
+	rng          *rand.Rand
+	subtreeDepth int
+	maxDepth     int
+}
+
+// NewTreeMutationSubtree creates a new mutation for [TreeGenotype]s that replaces a random subtree with a new one created with the grow method.
+// The new subtree has a depth of at most subtreeDepth, and is made smaller if needed so that the tree is no deeper than maxDepth.
+func NewTreeMutationSubtree(rng *rand.Rand, subtreeDepth, maxDepth int) Mutation[*TreeGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if subtreeDepth < 0 || maxDepth < 0 {
+		panic("cannot have negative depths")
+	}
+	return &treeMutationSubtree{
+		rng:          rng,
+		subtreeDepth: subtreeDepth,
+		maxDepth:     maxDepth,
+	}
+}
+
+// Mutate implements [Mutation].
+func (m *treeMutationSubtree) Mutate(g *TreeGenotype) {
+	i := m.rng.IntN(len(g.nodes))
+	depth := max(0, min(m.subtreeDepth, m.maxDepth-g.nodeDepths()[i]))
+	g.replaceSubtree(i, g.primitives.randomTree(m.rng, depth, false))
+}
+
+// treeMutationPoint is a mutation that replaces nodes with other primitives of the same arity.
+type treeMutationPoint struct {
+	rng    *rand.Rand
+	chance float64
+}
+
+// NewTreeMutationPoint creates a new mutation for [TreeGenotype]s where each node, with the given chance,
+// is replaced by a random function with the same arity, or a random terminal if it is a terminal.
+// The shape of the tree never changes.
+func NewTreeMutationPoint(rng *rand.Rand, chance float64) Mutation[*TreeGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	if chance < 0 || chance > 1 {
+		panic("cannot have chance out of range 0-1")
+	}
+	return &treeMutationPoint{
+		rng:    rng,
+		chance: chance,
+	}
+}
+
+// Mutate implements [Mutation].
+func (m *treeMutationPoint) Mutate(g *TreeGenotype) {
+	for i, n := range g.nodes {
+		if m.rng.Float64() >= m.chance {
+			continue
+		}
+		if n.function == -1 {
+			g.nodes[i] = g.primitives.randomTerminal(m.rng)
+			continue
+		}
+		var options []int
+		for f, fn := range g.primitives.functions {
+			if fn.Arity == g.arity(n) {
+				options = append(options, f)
+			}
+		}
+		g.nodes[i].function = options[m.rng.IntN(len(options))]
+	}
+}
+
+// treeMutationHoist is a mutation that replaces the tree with one of its own subtrees.
+type treeMutationHoist struct {
+	rng *rand.Rand
+}
+
+// NewTreeMutationHoist creates a new mutation for [TreeGenotype]s that replaces the whole tree with a random subtree of itself.
+// This can only make the tree smaller, so it helps to control bloat.
+func NewTreeMutationHoist(rng *rand.Rand) Mutation[*TreeGenotype] {
+	if rng == nil {
+		panic("cannot have nil rng")
+	}
+	return &treeMutationHoist{
+		rng: rng,
+	}
+}
+
+// Mutate implements [Mutation].
+func (m *treeMutationHoist) Mutate(g *TreeGenotype) {
+	i := m.rng.IntN(len(g.nodes))
+	g.nodes = slices.Clone(g.nodes[i:g.subtreeEnd(i)])
+}
@@ -0,0 +1,90 @@
+package goevo
+
+import (
+	"math"
+	"testing"
+)
+
+// Check that a hand-built tree evaluates, prints, and validates correctly
+func TestTreeGenotype(t *testing.T) {
+	prims := NewTreePrimitives(2, NewGeneratorNormal(NewRand(0), 0.0, 1.0), TreeAdd, TreeMul, TreeSin)
+	// (add x0 (mul x1 2.5))
+	g := &TreeGenotype{primitives: prims, nodes: []treeNode{
+		{function: 0, variable: -1},
+		{function: -1, variable: 0},
+		{function: 1, variable: -1},
+		{function: -1, variable: 1},
+		{function: -1, variable: -1, constant: 2.5},
+	}}
+	assertEq(t, g.Forward([]float64{1, 2})[0], 6.0, "forward")
+	assertEq(t, g.String(), "(add x0 (mul x1 2.5))", "string")
+	assertEq(t, g.Size(), 5, "size")
+	assertEq(t, g.Depth(), 2, "depth")
+	assertEq(t, g.subtreeEnd(2), 5, "subtree end")
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	c := Clone(g)
+	c.nodes[1].variable = 1
+	assertEq(t, g.nodes[1].variable, 0, "clone is deep")
+	c.nodes = c.nodes[:4]
+	if c.Validate() == nil {
+		t.Fatal("tree with a missing node was valid")
+	}
+}
+
+// Check that ramped half-and-half creates trees of every depth in the range, and that the operators keep trees valid and within the depth limit
+func TestTreeOperators(t *testing.T) {
+	rng := NewRand(0)
+	prims := NewTreePrimitives(2, NewGeneratorNormal(rng, 0.0, 1.0), TreeAdd, TreeSub, TreeMul, TreeSin)
+	newGenotype := NewTreeRampedHalfAndHalf(rng, prims, 2, 4)
+	seenDepths := make(map[int]bool)
+	trees := make([]*TreeGenotype, 30)
+	for i := range trees {
+		trees[i] = newGenotype()
+		if i%2 == 0 {
+			// Full trees always reach the depth they were created with
+			assertEq(t, trees[i].Depth(), 2+(i/2)%3, "full tree depth")
+		}
+		seenDepths[trees[i].Depth()] = true
+	}
+	for d := 2; d <= 4; d++ {
+		assertEq(t, seenDepths[d], true, "ramped depths")
+	}
+
+	const maxDepth = 6
+	crossover := NewTreeCrossoverSubtree(rng, maxDepth)
+	mutations := []Mutation[*TreeGenotype]{
+		NewTreeMutationSubtree(rng, 3, maxDepth),
+		NewTreeMutationPoint(rng, 0.2),
+		NewTreeMutationHoist(rng),
+	}
+	for i := range 500 {
+		a, b := trees[rng.IntN(len(trees))], trees[rng.IntN(len(trees))]
+		child := crossover.Crossover([]*TreeGenotype{a, b})
+		mutations[i%len(mutations)].Mutate(child)
+		if err := child.Validate(); err != nil {
+			t.Fatalf("invalid tree %v: %v", child, err)
+		}
+		if child.Depth() > maxDepth {
+			t.Fatalf("tree %v was deeper than %v", child, maxDepth)
+		}
+		trees[rng.IntN(len(trees))] = child
+	}
+}
+
+// Check that tree genetic programming can find a simple expression
+func TestTreeSymbolicRegression(t *testing.T) {
+	rng := NewRand(0)
+	prims := NewTreePrimitives(1, NewGeneratorNormal(rng, 0.0, 1.0), TreeAdd, TreeSub, TreeMul, TreeDiv)
+	reprod := NewTwoPhaseReproduction(NewTreeCrossoverSubtree(rng, 8), NewTreeMutationPoint(rng, 0.05))
+	pop := NewSimplePopulation(NewTreeRampedHalfAndHalf(rng, prims, 1, 4), 200, NewTournamentSelection[*TreeGenotype](rng, 5), reprod)
+	fitness := func(g *TreeGenotype) float64 {
+		total := 0.0
+		for x := -1.0; x <= 1; x += 0.2 {
+			total -= math.Abs(g.Forward([]float64{x})[0] - (x*x*x + x*x + x))
+		}
+		return total
+	}
+	testWithFitnessFunc(t, fitness, pop)
+}
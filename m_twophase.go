@@ -31,7 +31,37 @@ func (r *twoPhaseReproduction[T]) Reproduce(parents []T) T {
 	return child
 }
 
+// ReproduceAgents implements the [AgentReproduction] interface.
+// If the crossover is an [AgentCrossover] it is given the agents, otherwise it is given their genotypes.
+func (r *twoPhaseReproduction[T]) ReproduceAgents(parents []*Agent[T]) T {
+	if len(parents) != r.crossover.NumParents() {
+		panic("incorrect number of parents")
+	}
+	ac, ok := r.crossover.(AgentCrossover[T])
+	if !ok {
+		gts := make([]T, len(parents))
+		for i, a := range parents {
+			gts[i] = a.Genotype
+		}
+		return r.Reproduce(gts)
+	}
+	child := ac.CrossoverAgents(parents)
+	r.mutate.Mutate(child)
+	return child
+}
+
 // NumParents implements the [Reproduction] interface.
 func (r *twoPhaseReproduction[T]) NumParents() int {
 	return r.crossover.NumParents()
 }
+
+// WithScaledStrength implements [Scalable] by scaling the mutation, which must also be [Scalable].
+func (r *twoPhaseReproduction[T]) WithScaledStrength(factor float64) any {
+	mutate, ok := r.mutate.(Scalable)
+	if !ok {
+		panic("two phase reproduction mutation is not scalable")
+	}
+	c := *r
+	c.mutate = mutate.WithScaledStrength(factor).(Mutation[T])
+	return &c
+}
@@ -0,0 +1,19 @@
+package goevo
+
+import "math/rand/v2"
+
+// NewRand creates a new random source from a seed.
+// Two sources created with the same seed will produce exactly the same stream of numbers,
+// so passing sources from the same seed to every component will reproduce a run exactly.
+//
+// A random source is not safe for concurrent use, so each goroutine should use its own source, created with [DeriveRand].
+func NewRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+// DeriveRand creates a new random source, seeded from the next values of rng.
+// The new source produces a different stream to rng, but is still reproducible if rng is.
+// This is useful to give each worker or component its own source.
+func DeriveRand(rng *rand.Rand) *rand.Rand {
+	return rand.New(rand.NewPCG(rng.Uint64(), rng.Uint64()))
+}
@@ -0,0 +1,53 @@
+package goevo
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func runSeededNeat(seed uint64) ([]GenerationStats, []byte) {
+	rng := NewRand(seed)
+	counter := NewCounter()
+	selec := NewTournamentSelection[*NeatGenotype](DeriveRand(rng), 3)
+	mut := NewNeatMutationStd(DeriveRand(rng), counter, AllSingleActivations, 1, 0.5, 0.5, 2, 0.5, 0.5, 0.2, 0.4, -1)
+	reprod := NewTwoPhaseReproduction(NewNeatCrossoverSimple(DeriveRand(rng)), mut)
+	pop := NewSpeciatedPopulation(DeriveRand(rng), counter, func() *NeatGenotype {
+		gt := NewNeatGenotype(counter, 3, 2, Sigmoid)
+		gt.AddRandomSynapse(rng, counter, 0.3, false)
+		return gt
+	}, 4, 10, 0.5, 2, selec, reprod)
+
+	fitness := func(g *NeatGenotype) float64 {
+		out := g.Build().Forward([]float64{0.3, -0.2, 1})
+		return out[0] - out[1]
+	}
+	rec := NewStatsRecorder[*NeatGenotype]()
+	runner := NewRunner(fitness, 4, NewStopMaxGenerations(30))
+	runner.OnGeneration(func(summary RunSummary, pop Population[*NeatGenotype]) error {
+		_, err := rec.Record(summary.Generations, pop)
+		return err
+	})
+	best, _ := runner.Run(pop)
+	bs, err := json.Marshal(best.Genotype)
+	if err != nil {
+		panic(err)
+	}
+	return rec.History(), bs
+}
+
+// Check that two runs from the same seed are bit-identical, even with parallel evaluation
+func TestSeededRunsReproducible(t *testing.T) {
+	historyA, bestA := runSeededNeat(42)
+	historyB, bestB := runSeededNeat(42)
+	if !slices.Equal(historyA, historyB) {
+		t.Fatalf("histories differ:\n%v\n%v", historyA, historyB)
+	}
+	if string(bestA) != string(bestB) {
+		t.Fatalf("best genotypes differ:\n%s\n%s", bestA, bestB)
+	}
+	historyC, _ := runSeededNeat(43)
+	if slices.Equal(historyA, historyC) {
+		t.Fatalf("different seeds gave identical histories")
+	}
+}
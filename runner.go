@@ -0,0 +1,186 @@
+package goevo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RunSummary describes the progress of a [Runner].
+// It is passed to each [StopCondition] after every generation, and returned once the run has finished.
+type RunSummary struct {
+	// Generations is the number of generations that have been evaluated.
+	Generations int
+	// Evaluations is the total number of fitness evaluations that have been performed.
+	Evaluations int
+	// BestFitness is the highest fitness seen so far.
+	BestFitness float64
+	// BestGeneration is the generation (counting from 1) in which BestFitness was first seen.
+	BestGeneration int
+	// Duration is the wall-clock time since the run started.
+	Duration time.Duration
+	// StopReason is the description of the [StopCondition] that ended the run,
+	// "cancelled" if the run was cancelled, or "observer error" if a function added with [Runner.OnGeneration] failed.
+	StopReason string
+}
+
+// Runner repeatedly evaluates the fitness of a [Population] and creates the next generation,
+// until one of its [StopCondition]s is met or its context is cancelled.
+// Fitness is evaluated concurrently on a fixed number of workers, so the fitness function must be safe to call from multiple goroutines.
+type Runner[T any] struct {
+	evaluate   func(context.Context, *Agent[T])
+	workers    int
+	conditions []StopCondition
+	observers  []func(RunSummary, Population[T]) error
+}
+
+// NewRunner creates a new [Runner] that evaluates agents with the fitness function on the given number of workers.
+// The run stops as soon as any of the conditions is met, so at least one condition is required.
+func NewRunner[T any](fitness func(T) float64, workers int, conditions ...StopCondition) *Runner[T] {
+	if fitness == nil {
+		panic("cannot have nil fitness function")
+	}
+	return NewContextRunner(func(_ context.Context, g T) float64 { return fitness(g) }, workers, conditions...)
+}
+
+// NewContextRunner creates a new [Runner] like [NewRunner], but the fitness function also receives the context passed to [Runner.RunContext].
+// Long fitness evaluations can use this to return early once the run has been cancelled.
+func NewContextRunner[T any](fitness func(context.Context, T) float64, workers int, conditions ...StopCondition) *Runner[T] {
+	if fitness == nil {
+		panic("cannot have nil fitness function")
+	}
+	return NewAgentRunner(func(ctx context.Context, a *Agent[T]) { a.Fitness = fitness(ctx, a.Genotype) }, workers, conditions...)
+}
+
+// NewAgentRunner creates a new [Runner] like [NewContextRunner], but the evaluation function receives the whole agent.
+// It must set the fitness of the agent, and may also set other fields such as [Agent.Objectives].
+// This is needed for populations that use more than the fitness, such as [NSGA2Population].
+func NewAgentRunner[T any](evaluate func(context.Context, *Agent[T]), workers int, conditions ...StopCondition) *Runner[T] {
+	if evaluate == nil {
+		panic("cannot have nil evaluate function")
+	}
+	if workers <= 0 {
+		panic("must have at least one worker")
+	}
+	if len(conditions) == 0 {
+		panic("must have at least one stop condition")
+	}
+	return &Runner[T]{
+		evaluate:   evaluate,
+		workers:    workers,
+		conditions: conditions,
+	}
+}
+
+// OnGeneration adds a function that is called after each generation has been evaluated, before the stop conditions are checked.
+// It can be used to record stats or write checkpoints, for example with [StatsRecorder.Record].
+// If the function returns an error, the run stops and returns that error.
+func (r *Runner[T]) OnGeneration(observer func(summary RunSummary, pop Population[T]) error) {
+	if observer == nil {
+		panic("cannot have nil observer")
+	}
+	r.observers = append(r.observers, observer)
+}
+
+// Run evolves the population until a stop condition is met.
+// It returns the best agent seen across all generations, and a summary of the run.
+// Use [Runner.RunContext] instead if you need to see errors returned by functions added with [Runner.OnGeneration].
+func (r *Runner[T]) Run(pop Population[T]) (*Agent[T], RunSummary) {
+	best, _, summary, _ := r.RunContext(context.Background(), pop)
+	return best, summary
+}
+
+// RunContext evolves the population until a stop condition is met or ctx is cancelled.
+// It returns the best agent seen across all generations, the last population that was fully evaluated, and a summary of the run.
+// If the population is an [IncrementalPopulation], only its unevaluated agents are evaluated each generation.
+//
+// Cancellation is only checked between evaluation batches, so a cancelled run will finish evaluating the current generation before returning.
+// In that case, the returned error is the error of the context, which can be used to tell a cancelled run apart from a finished one.
+// This makes it simple to shut down gracefully on a signal, for example by using a context from [os/signal.NotifyContext].
+func (r *Runner[T]) RunContext(ctx context.Context, pop Population[T]) (*Agent[T], Population[T], RunSummary, error) {
+	start := time.Now()
+	summary := RunSummary{BestFitness: math.Inf(-1)}
+	var best *Agent[T]
+	if err := ctx.Err(); err != nil {
+		summary.StopReason = "cancelled"
+		return best, pop, summary, err
+	}
+	for {
+		agents := pop.All()
+		unevaluated := agents
+		if ip, ok := pop.(IncrementalPopulation[T]); ok {
+			unevaluated = ip.Unevaluated()
+		}
+		EvaluateAgents(ctx, unevaluated, r.evaluate, r.workers)
+		summary.Generations++
+		summary.Evaluations += len(unevaluated)
+		for _, a := range agents {
+			if best == nil || a.Fitness > summary.BestFitness {
+				best = a
+				summary.BestFitness = a.Fitness
+				summary.BestGeneration = summary.Generations
+			}
+		}
+		summary.Duration = time.Since(start)
+		for _, o := range r.observers {
+			if err := o(summary, pop); err != nil {
+				summary.StopReason = "observer error"
+				return best, pop, summary, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			summary.StopReason = "cancelled"
+			return best, pop, summary, err
+		}
+		for _, c := range r.conditions {
+			if c.ShouldStop(summary) {
+				summary.StopReason = c.String()
+				return best, pop, summary, nil
+			}
+		}
+		next := pop.NextGeneration()
+		// Creating the next generation can take a while, so make sure we still return the evaluated population if we were cancelled meanwhile
+		if err := ctx.Err(); err != nil {
+			summary.StopReason = "cancelled"
+			return best, pop, summary, err
+		}
+		pop = next
+	}
+}
+
+// EvaluateFitness sets the fitness of every agent using the fitness function, spread over the given number of workers.
+// It blocks until all agents have been evaluated.
+func EvaluateFitness[T any](agents []*Agent[T], fitness func(T) float64, workers int) {
+	EvaluateFitnessContext(context.Background(), agents, func(_ context.Context, g T) float64 { return fitness(g) }, workers)
+}
+
+// EvaluateFitnessContext is like [EvaluateFitness], but passes ctx to the fitness function.
+// Every agent is still evaluated if ctx is cancelled, so it is up to the fitness function to return early if it wishes.
+func EvaluateFitnessContext[T any](ctx context.Context, agents []*Agent[T], fitness func(context.Context, T) float64, workers int) {
+	EvaluateAgents(ctx, agents, func(ctx context.Context, a *Agent[T]) { a.Fitness = fitness(ctx, a.Genotype) }, workers)
+}
+
+// EvaluateAgents calls evaluate on every agent, spread over the given number of workers.
+// Unlike [EvaluateFitnessContext], evaluate is responsible for setting the fitness (and any other fields) of the agent.
+func EvaluateAgents[T any](ctx context.Context, agents []*Agent[T], evaluate func(context.Context, *Agent[T]), workers int) {
+	if workers <= 0 {
+		panic("must have at least one worker")
+	}
+	jobs := make(chan *Agent[T])
+	wg := &sync.WaitGroup{}
+	for range min(workers, len(agents)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range jobs {
+				evaluate(ctx, a)
+			}
+		}()
+	}
+	for _, a := range agents {
+		jobs <- a
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,76 @@
+package goevo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func setupRunnerTestStuff() Population[*ArrayGenotype[float64]] {
+	rng := NewRand(0)
+	mut := NewArrayMutationGeneratorAdd(NewGeneratorNormal(rng, 0, 0.05), 0.1)
+	reprod := NewTwoPhaseReproduction(NewArrayCrossoverUniform[float64](rng), mut)
+	selec := NewTournamentSelection[*ArrayGenotype[float64]](rng, 3)
+	return NewSimplePopulation(func() *ArrayGenotype[float64] {
+		return NewArrayGenotype(5, NewGeneratorNormal(rng, 0, 0.5))
+	}, 50, selec, reprod)
+}
+
+// Check that every agent is evaluated exactly once per generation, no matter how many workers there are
+func TestEvaluateFitness(t *testing.T) {
+	for _, workers := range []int{1, 3, 100} {
+		agents := make([]*Agent[int], 20)
+		for i := range agents {
+			agents[i] = NewAgent(i)
+		}
+		EvaluateFitness(agents, func(g int) float64 { return float64(g * 2) }, workers)
+		for i, a := range agents {
+			assertEq(t, a.Fitness, float64(i*2), "fitness")
+		}
+	}
+}
+
+// Check that the runner stops at the first stop condition that is met
+func TestRunnerStopConditions(t *testing.T) {
+	fitness := func(g *ArrayGenotype[float64]) float64 { return 0 }
+
+	_, summary := NewRunner(fitness, 4, NewStopMaxGenerations(7)).Run(setupRunnerTestStuff())
+	assertEq(t, summary.Generations, 7, "generations")
+	assertEq(t, summary.Evaluations, 7*50, "evaluations")
+
+	// Fitness never improves after the first generation
+	_, summary = NewRunner(fitness, 4, NewStopStagnation(3), NewStopMaxGenerations(100)).Run(setupRunnerTestStuff())
+	assertEq(t, summary.Generations, 4, "stagnation generations")
+	assertEq(t, summary.BestGeneration, 1, "best generation")
+	assertEq(t, summary.StopReason, NewStopStagnation(3).String(), "stop reason")
+
+	best, summary := NewRunner(func(g *ArrayGenotype[float64]) float64 { return g.At(0) }, 4, NewStopTargetFitness(-100)).Run(setupRunnerTestStuff())
+	assertEq(t, summary.Generations, 1, "target generations")
+	assertEq(t, best.Fitness, summary.BestFitness, "best fitness")
+}
+
+// Check that cancelling a run finishes the current batch and returns the evaluated population
+func TestRunnerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	evals := &atomic.Int64{}
+	fitness := func(ctx context.Context, g *ArrayGenotype[float64]) float64 {
+		// Cancel part way through the third generation
+		if evals.Add(1) == 2*50+10 {
+			cancel()
+		}
+		return 1
+	}
+	runner := NewContextRunner(fitness, 4, NewStopMaxGenerations(100))
+	best, pop, summary, err := runner.RunContext(ctx, setupRunnerTestStuff())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled error, got %v", err)
+	}
+	assertEq(t, summary.Generations, 3, "generations")
+	assertEq(t, int(evals.Load()), 3*50, "evaluations")
+	assertEq(t, best.Fitness, 1.0, "best fitness")
+	for _, a := range pop.All() {
+		assertEq(t, a.Fitness, 1.0, "population fitness")
+	}
+}
@@ -0,0 +1,112 @@
+package goevo
+
+import (
+	"math"
+	"slices"
+)
+
+// neatStructure is implemented by genotypes with a NEAT-like structure, such as [NeatGenotype].
+type neatStructure interface {
+	NumHiddenNeurons() int
+	NumSynapses() int
+}
+
+// GenerationStats is a summary of the agents in one generation of a population.
+type GenerationStats struct {
+	// Generation is the generation number these stats were recorded for.
+	Generation int `json:"generation"`
+	// NumAgents is the number of agents in the population.
+	NumAgents int `json:"num_agents"`
+	// Best is the highest fitness of any agent.
+	Best float64 `json:"best"`
+	// Mean is the mean fitness of all agents.
+	Mean float64 `json:"mean"`
+	// Median is the median fitness of all agents.
+	Median float64 `json:"median"`
+	// Worst is the lowest fitness of any agent.
+	Worst float64 `json:"worst"`
+	// Std is the standard deviation of the fitness of all agents.
+	Std float64 `json:"std"`
+	// NumSpecies is the number of species in the population, or 0 if the population does not have species.
+	NumSpecies int `json:"num_species"`
+	// MeanHiddenNeurons is the mean number of hidden neurons of the genotypes, or 0 if they are not NEAT genotypes.
+	MeanHiddenNeurons float64 `json:"mean_hidden_neurons"`
+	// MeanSynapses is the mean number of synapses of the genotypes, or 0 if they are not NEAT genotypes.
+	MeanSynapses float64 `json:"mean_synapses"`
+}
+
+// NewGenerationStats calculates the [GenerationStats] of the population.
+// The fitness of every agent should already have been evaluated.
+func NewGenerationStats[T any](generation int, pop Population[T]) GenerationStats {
+	agents := pop.All()
+	stats := GenerationStats{Generation: generation, NumAgents: len(agents)}
+	if len(agents) == 0 {
+		return stats
+	}
+	fitnesses := make([]float64, len(agents))
+	for i, a := range agents {
+		fitnesses[i] = a.Fitness
+		stats.Mean += a.Fitness
+		if g, ok := any(a.Genotype).(neatStructure); ok {
+			stats.MeanHiddenNeurons += float64(g.NumHiddenNeurons())
+			stats.MeanSynapses += float64(g.NumSynapses())
+		}
+	}
+	n := float64(len(agents))
+	stats.Mean /= n
+	stats.MeanHiddenNeurons /= n
+	stats.MeanSynapses /= n
+	slices.Sort(fitnesses)
+	stats.Worst = fitnesses[0]
+	stats.Best = fitnesses[len(fitnesses)-1]
+	if len(fitnesses)%2 == 1 {
+		stats.Median = fitnesses[len(fitnesses)/2]
+	} else {
+		stats.Median = (fitnesses[len(fitnesses)/2-1] + fitnesses[len(fitnesses)/2]) / 2
+	}
+	for _, f := range fitnesses {
+		stats.Std += (f - stats.Mean) * (f - stats.Mean)
+	}
+	stats.Std = math.Sqrt(stats.Std / n)
+	if sp, ok := pop.(speciesPopulation[T]); ok {
+		stats.NumSpecies = len(sp.AllSpecies())
+	}
+	return stats
+}
+
+// StatsRecorder records the [GenerationStats] of a population each generation,
+// keeping a history and streaming each record to its [StatsWriter]s.
+type StatsRecorder[T any] struct {
+	history []GenerationStats
+	writers []StatsWriter
+}
+
+// NewStatsRecorder creates a new [StatsRecorder] that writes every record to each of the writers.
+func NewStatsRecorder[T any](writers ...StatsWriter) *StatsRecorder[T] {
+	for _, w := range writers {
+		if w == nil {
+			panic("cannot have nil writer")
+		}
+	}
+	return &StatsRecorder[T]{
+		writers: writers,
+	}
+}
+
+// Record calculates the stats of the population, adds them to the history, and writes them to every writer.
+// It returns the calculated stats, and the first error returned by a writer.
+func (r *StatsRecorder[T]) Record(generation int, pop Population[T]) (GenerationStats, error) {
+	stats := NewGenerationStats(generation, pop)
+	r.history = append(r.history, stats)
+	for _, w := range r.writers {
+		if err := w.Write(stats); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// History returns every record that has been made, in the order they were recorded.
+func (r *StatsRecorder[T]) History() []GenerationStats {
+	return slices.Clone(r.history)
+}
@@ -0,0 +1,86 @@
+package goevo
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Check the fitness stats are correct for a known set of fitnesses
+func TestGenerationStats(t *testing.T) {
+	pop := NewSimplePopulation(func() int { return 0 }, 4, NewEliteSelection[int](), NewTwoPhaseReproduction[int](&intCrossoverAsexual{}, &intMutationNone{}))
+	for i, a := range pop.All() {
+		a.Fitness = []float64{4, 1, 3, 8}[i]
+	}
+	s := NewGenerationStats(3, pop)
+	assertEq(t, s.Generation, 3, "generation")
+	assertEq(t, s.NumAgents, 4, "num agents")
+	assertEq(t, s.Best, 8.0, "best")
+	assertEq(t, s.Worst, 1.0, "worst")
+	assertEq(t, s.Mean, 4.0, "mean")
+	assertEq(t, s.Median, 3.5, "median")
+	assertEq(t, s.Std, math.Sqrt(6.5), "std")
+	assertEq(t, s.NumSpecies, 0, "species")
+}
+
+// Check the recorder streams neat structure stats to both formats while running
+func TestStatsRecorder(t *testing.T) {
+	csvBuf, jsonBuf := new(bytes.Buffer), new(bytes.Buffer)
+	rec := NewStatsRecorder[*NeatGenotype](NewCSVStatsWriter(csvBuf), NewJSONStatsWriter(jsonBuf))
+	rng := NewRand(0)
+	counter := NewCounter()
+	selec := NewTournamentSelection[*NeatGenotype](rng, 3)
+	mut := NewNeatMutationStd(rng, counter, AllSingleActivations, 1, 0, 0.5, 2, 0, 0.5, 0.2, 0.4, 3)
+	reprod := NewTwoPhaseReproduction(NewNeatCrossoverSimple(rng), mut)
+	pop := NewSpeciatedPopulation(rng, counter, func() *NeatGenotype {
+		gt := NewNeatGenotype(counter, 2, 1, Sigmoid)
+		gt.AddRandomSynapse(rng, counter, 0.3, false)
+		return gt
+	}, 2, 5, 0, 0, selec, reprod)
+
+	runner := NewRunner(func(g *NeatGenotype) float64 { return float64(g.NumSynapses()) }, 2, NewStopMaxGenerations(5))
+	runner.OnGeneration(func(summary RunSummary, pop Population[*NeatGenotype]) error {
+		_, err := rec.Record(summary.Generations, pop)
+		return err
+	})
+	runner.Run(pop)
+
+	history := rec.History()
+	assertEq(t, len(history), 5, "history length")
+	for _, s := range history {
+		assertEq(t, s.NumSpecies, 2, "species")
+		if s.MeanSynapses < 1 {
+			t.Fatalf("expected at least one synapse on average, got %v", s.MeanSynapses)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(csvBuf.String()), "\n")
+	assertEq(t, len(lines), 6, "csv lines")
+	assertEq(t, strings.Split(lines[0], ",")[0], "generation", "csv header")
+	dec := json.NewDecoder(jsonBuf)
+	for i := range 5 {
+		var s GenerationStats
+		if err := dec.Decode(&s); err != nil {
+			t.Fatal(err)
+		}
+		assertEq(t, s, history[i], "json record")
+	}
+}
+
+// Check that non-finite fitness stats are written as null instead of failing
+func TestJSONStatsWriterInf(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := NewJSONStatsWriter(buf).Write(GenerationStats{Generation: 1, NumAgents: 2, Best: math.Inf(1), Mean: math.Inf(1), Worst: 3, Std: math.NaN()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, record["best"], nil, "best")
+	assertEq(t, record["std"], nil, "std")
+	assertEq(t, record["worst"], any(3.0), "worst")
+	assertEq(t, record["generation"], any(1.0), "generation")
+}
@@ -1,9 +1,12 @@
 package goevo
 
 import (
+	"cmp"
 	"fmt"
 	"math"
-	"math/rand"
+	"math/rand/v2"
+	"slices"
+	"sort"
 	"strings"
 
 	"gonum.org/v1/gonum/mat"
@@ -17,24 +20,22 @@ type numberType interface {
 	floatType | int | int16 | int32 | int64
 }
 
-func stdN(std float64) int {
-	v := math.Abs(rand.NormFloat64() * std)
+func stdN(rng *rand.Rand, std float64) int {
+	v := math.Abs(rng.NormFloat64() * std)
 	if v > std*10 {
 		v = std * 10 // Lets just cap this at 10 std to prevent any sillyness
 	}
 	return int(math.Round(v))
 }
 
-func randomMapKey[T comparable, U any](m map[T]U) T {
-	n := rand.Intn(len(m))
-	i := 0
+// sortedKeys returns the keys of the map in ascending order.
+func sortedKeys[T cmp.Ordered, U any](m map[T]U) []T {
+	keys := make([]T, 0, len(m))
 	for k := range m {
-		if i == n {
-			return k
-		}
-		i++
+		keys = append(keys, k)
 	}
-	panic("cannot occur")
+	slices.Sort(keys)
+	return keys
 }
 
 func min(a, b int) int {
@@ -51,6 +52,13 @@ func max(a, b int) int {
 	return b
 }
 
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
 func clamp(x, min, max float64) float64 {
 	if x < min {
 		return min
@@ -61,6 +69,66 @@ func clamp(x, min, max float64) float64 {
 	return x
 }
 
+// rescoredSelection wraps a selection so that it selects using a score other than the fitness of each agent,
+// without modifying the agents themselves.
+// The wrapped selection sees copies of the agents with their fitness replaced by the score, and selections are mapped back to the original agents.
+type rescoredSelection[T any] struct {
+	inner     Selection[T]
+	originals map[*Agent[T]]*Agent[T]
+}
+
+func newRescoredSelection[T any](inner Selection[T]) *rescoredSelection[T] {
+	if inner == nil {
+		panic("cannot have nil selection")
+	}
+	return &rescoredSelection[T]{inner: inner}
+}
+
+func (s *rescoredSelection[T]) setAgents(agents []*Agent[T], scores []float64) {
+	if len(agents) != len(scores) {
+		panic("must have one score per agent")
+	}
+	s.originals = make(map[*Agent[T]]*Agent[T], len(agents))
+	copies := make([]*Agent[T], len(agents))
+	for i, a := range agents {
+		c := *a
+		c.Fitness = scores[i]
+		copies[i] = &c
+		s.originals[copies[i]] = a
+	}
+	s.inner.SetAgents(copies)
+}
+
+func (s *rescoredSelection[T]) selectAgent() *Agent[T] {
+	a, ok := s.originals[s.inner.Select()]
+	if !ok {
+		panic("selection returned an agent it was not given")
+	}
+	return a
+}
+
+// copyElites returns copies of the n fittest agents, fittest first.
+// The genotypes are cloned if they are [Cloneable], so an elite that is copied more than once can be evaluated concurrently.
+func copyElites[T any](agents []*Agent[T], n int) []*Agent[T] {
+	sorted := slices.Clone(agents)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Fitness > sorted[j].Fitness })
+	elites := make([]*Agent[T], min(n, len(sorted)))
+	for i := range elites {
+		e := *sorted[i]
+		e.Genotype = cloneIfCloneable(e.Genotype)
+		elites[i] = &e
+	}
+	return elites
+}
+
+// cloneIfCloneable returns a clone of g if it is [Cloneable], or g itself otherwise.
+func cloneIfCloneable[T any](g T) T {
+	if c, ok := any(g).(Cloneable); ok {
+		return c.Clone().(T)
+	}
+	return g
+}
+
 type mutMat interface {
 	mat.Matrix
 	Set(r, c int, v float64)
@@ -78,11 +146,11 @@ func (m *mutVecWrapper) Set(r, c int, v float64) {
 }
 
 // make sure to check the shapes first!!
-func randomChoiceMatrix(into mutMat, ms []mutMat) {
+func randomChoiceMatrix(rng *rand.Rand, into mutMat, ms []mutMat) {
 	rs, cs := into.Dims()
 	for ri := range rs {
 		for ci := range cs {
-			idx := rand.Intn(len(ms))
+			idx := rng.IntN(len(ms))
 			into.Set(ri, ci, ms[idx].At(ri, ci))
 		}
 	}
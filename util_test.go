@@ -3,6 +3,7 @@ package goevo
 import (
 	"fmt"
 	"math"
+	"runtime"
 	"testing"
 )
 
@@ -68,35 +69,27 @@ func testWithDataset[T any](t *testing.T, X, Y [][]float64, pop Population[T], b
 }
 
 func testWithFitnessFunc[T any](t *testing.T, fitness func(T) float64, pop Population[T]) {
-	var maxFitness float64
-	var maxGt T
-	for gen := 0; gen < 5000; gen++ {
-		maxFitness = math.Inf(-1)
-		maxGt = *new(T)
-		for _, a := range pop.All() {
-			a.Fitness = fitness(a.Genotype)
-			if a.Fitness > maxFitness {
-				maxFitness = a.Fitness
-				maxGt = a.Genotype
-			}
-		}
-
-		if maxFitness > -0.1 {
-			break
-		}
-
-		pop = NextGeneration(pop)
+	runner := NewRunner(fitness, runtime.NumCPU(), NewStopTargetFitness(-0.1), NewStopMaxGenerations(5000))
+	best, summary := runner.Run(pop)
+	if best.Fitness < -0.1 {
+		t.Fatalf("Failed to converge, ending with fitness %f (%s)", best.Fitness, summary.StopReason)
 	}
-	if maxFitness < -0.1 {
-		t.Fatalf("Recurrency Failed to converge, ending with fitness %f", maxFitness)
-	}
-	val, ok := any(maxGt).(Validateable)
+	val, ok := any(best.Genotype).(Validateable)
 	if ok {
 		if err := val.Validate(); err != nil {
-			t.Fatalf("final genotype was not valid: %v\nGenotype:\n%v", err, maxGt)
+			t.Fatalf("final genotype was not valid: %v\nGenotype:\n%v", err, best.Genotype)
 		}
 	}
-	fmt.Println("max fitness", maxFitness)
+	fmt.Println("max fitness", best.Fitness, "after", summary.Generations, "generations")
+}
+
+// distanceToOnesFitness is a fitness function for array genotypes, which is highest (0) when every value is 1.
+func distanceToOnesFitness(g *ArrayGenotype[float64]) float64 {
+	total := 0.0
+	for i := range g.Len() {
+		total -= math.Abs(g.At(i) - 1)
+	}
+	return total
 }
 
 func assertEq[T comparable](t *testing.T, a T, b T, name string) {